@@ -0,0 +1,491 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	selectStatement = iota
+	insertStatement
+	updateStatement
+	deleteStatement
+)
+
+// Builder builds SELECT, INSERT, UPDATE and DELETE statements whose query and
+// args go straight into the Mysql and Tx methods:
+//
+//	query, args, err := Select("id", "name").From("users").
+//		Where("status = ?", 1).In("group_id", 3, 4).
+//		OrderBy("created_at DESC").Limit(20).ToSQL()
+//	rows, err := m.QueryForMapSlice(query, args...)
+//
+// Table and column names must be plain names, name or table.name, and are
+// quoted with backticks; anything else is an error from ToSQL. Conditions
+// and the Expr methods, such as SelectExpr("COUNT(*)"), are written as given
+// and must not come from user input; values always go through ?
+// placeholders.
+type Builder struct {
+	statement int
+	table     string
+	columns   []string
+	joins     []string
+	joinArgs  []interface{}
+	where     []condition
+	groupBy   []string
+	having    []condition
+	orderBy   []string
+	limit     int
+	offset    int
+	forUpdate bool
+
+	sets    []string
+	setArgs []interface{}
+	rows    [][]interface{}
+	naming  NamingStrategy
+	err     error
+}
+
+// WithNaming sets the naming strategy used by ColumnsOf and Filter,
+// DefaultNaming unless set.
+func (b *Builder) WithNaming(naming NamingStrategy) *Builder {
+	b.naming = naming
+	return b
+}
+
+func (b *Builder) namingStrategy() NamingStrategy {
+	if b.naming == nil {
+		return DefaultNaming
+	}
+	return b.naming
+}
+
+// setErr records the first error, reported by ToSQL.
+func (b *Builder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+type condition struct {
+	or   bool
+	sql  string
+	args []interface{}
+}
+
+func newBuilder(statement int, table string) *Builder {
+	return &Builder{statement: statement, table: table, limit: -1}
+}
+
+// Select starts a SELECT of columns, each a name with an optional alias,
+// "*" or "table.*".
+func Select(columns ...string) *Builder {
+	b := newBuilder(selectStatement, "")
+	for _, c := range columns {
+		quoted, err := quoteSelectColumn(c)
+		if err != nil {
+			b.setErr(err)
+			continue
+		}
+		b.columns = append(b.columns, quoted)
+	}
+	return b
+}
+
+// SelectExpr adds expressions to the columns of a SELECT, as given.
+func (b *Builder) SelectExpr(exprs ...string) *Builder {
+	b.columns = append(b.columns, exprs...)
+	return b
+}
+
+func InsertInto(table string) *Builder {
+	return newBuilder(insertStatement, table)
+}
+
+func Update(table string) *Builder {
+	return newBuilder(updateStatement, table)
+}
+
+func DeleteFrom(table string) *Builder {
+	return newBuilder(deleteStatement, table)
+}
+
+func (b *Builder) From(table string) *Builder {
+	b.table = table
+	return b
+}
+
+func (b *Builder) join(kind string, table string, on string, args []interface{}) *Builder {
+	quoted, err := quoteTable(table)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	b.joins = append(b.joins, kind+" "+quoted+" ON "+on)
+	b.joinArgs = append(b.joinArgs, args...)
+	return b
+}
+
+func (b *Builder) Join(table string, on string, args ...interface{}) *Builder {
+	return b.join("JOIN", table, on, args)
+}
+
+func (b *Builder) LeftJoin(table string, on string, args ...interface{}) *Builder {
+	return b.join("LEFT JOIN", table, on, args)
+}
+
+func (b *Builder) RightJoin(table string, on string, args ...interface{}) *Builder {
+	return b.join("RIGHT JOIN", table, on, args)
+}
+
+// Where adds a condition with AND. And binds tighter than Or, as in SQL.
+func (b *Builder) Where(cond string, args ...interface{}) *Builder {
+	b.where = append(b.where, condition{sql: cond, args: args})
+	return b
+}
+
+func (b *Builder) And(cond string, args ...interface{}) *Builder {
+	return b.Where(cond, args...)
+}
+
+func (b *Builder) Or(cond string, args ...interface{}) *Builder {
+	b.where = append(b.where, condition{or: true, sql: cond, args: args})
+	return b
+}
+
+// In adds column IN (values) with AND. No values never match. A single slice
+// value is expanded when the query runs.
+func (b *Builder) In(column string, values ...interface{}) *Builder {
+	quoted, err := quoteColumn(column)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	if len(values) == 0 {
+		return b.Where("1 = 0")
+	}
+	return b.Where(quoted+" IN ("+placeholders(len(values))+")", values...)
+}
+
+// NotIn adds column NOT IN (values) with AND. No values always match.
+func (b *Builder) NotIn(column string, values ...interface{}) *Builder {
+	quoted, err := quoteColumn(column)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	if len(values) == 0 {
+		return b
+	}
+	return b.Where(quoted+" NOT IN ("+placeholders(len(values))+")", values...)
+}
+
+func (b *Builder) Between(column string, from interface{}, to interface{}) *Builder {
+	quoted, err := quoteColumn(column)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	return b.Where(quoted+" BETWEEN ? AND ?", from, to)
+}
+
+func (b *Builder) GroupBy(columns ...string) *Builder {
+	for _, c := range columns {
+		quoted, err := quoteColumn(c)
+		if err != nil {
+			b.setErr(err)
+			continue
+		}
+		b.groupBy = append(b.groupBy, quoted)
+	}
+	return b
+}
+
+// GroupByExpr adds expressions to the GROUP BY, as given.
+func (b *Builder) GroupByExpr(exprs ...string) *Builder {
+	b.groupBy = append(b.groupBy, exprs...)
+	return b
+}
+
+func (b *Builder) Having(cond string, args ...interface{}) *Builder {
+	b.having = append(b.having, condition{sql: cond, args: args})
+	return b
+}
+
+// OrderBy takes columns with an optional ASC or DESC, like "created_at DESC".
+func (b *Builder) OrderBy(columns ...string) *Builder {
+	for _, c := range columns {
+		quoted, err := quoteOrder(c)
+		if err != nil {
+			b.setErr(err)
+			continue
+		}
+		b.orderBy = append(b.orderBy, quoted)
+	}
+	return b
+}
+
+// OrderByExpr adds expressions to the ORDER BY, as given, like
+// "FIELD(status, 'open', 'closed')".
+func (b *Builder) OrderByExpr(exprs ...string) *Builder {
+	b.orderBy = append(b.orderBy, exprs...)
+	return b
+}
+
+func (b *Builder) Limit(limit int) *Builder {
+	b.limit = limit
+	return b
+}
+
+func (b *Builder) Offset(offset int) *Builder {
+	b.offset = offset
+	return b
+}
+
+// ForUpdate adds FOR UPDATE to a SELECT, locking the rows in a Tx.
+func (b *Builder) ForUpdate() *Builder {
+	b.forUpdate = true
+	return b
+}
+
+// Set sets a column in an UPDATE, or adds it to the row of an INSERT.
+func (b *Builder) Set(column string, value interface{}) *Builder {
+	quoted, err := quoteColumn(column)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	switch b.statement {
+	case insertStatement:
+		b.columns = append(b.columns, quoted)
+		if len(b.rows) == 0 {
+			b.rows = append(b.rows, nil)
+		}
+		b.rows[0] = append(b.rows[0], value)
+	default:
+		b.sets = append(b.sets, quoted+" = ?")
+		b.setArgs = append(b.setArgs, value)
+	}
+	return b
+}
+
+// SetExpr sets a column in an UPDATE to an expression, like "`hits` + ?".
+func (b *Builder) SetExpr(column string, expr string, args ...interface{}) *Builder {
+	quoted, err := quoteColumn(column)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	b.sets = append(b.sets, quoted+" = "+expr)
+	b.setArgs = append(b.setArgs, args...)
+	return b
+}
+
+// Columns sets the columns of an INSERT, filled by Values.
+func (b *Builder) Columns(columns ...string) *Builder {
+	b.columns = make([]string, 0, len(columns))
+	for _, c := range columns {
+		quoted, err := quoteColumn(c)
+		if err != nil {
+			b.setErr(err)
+			continue
+		}
+		b.columns = append(b.columns, quoted)
+	}
+	return b
+}
+
+// Values adds a row to an INSERT.
+func (b *Builder) Values(values ...interface{}) *Builder {
+	if len(values) != len(b.columns) {
+		b.setErr(fmt.Errorf("mysql: insert into %s has %d columns but %d values", b.table, len(b.columns), len(values)))
+	}
+	b.rows = append(b.rows, values)
+	return b
+}
+
+func renderConditions(conds []condition, args []interface{}) (string, []interface{}) {
+	var sb strings.Builder
+	for i, c := range conds {
+		if i > 0 {
+			if c.or {
+				sb.WriteString(" OR ")
+			} else {
+				sb.WriteString(" AND ")
+			}
+		}
+		if len(conds) > 1 {
+			sb.WriteString("(" + c.sql + ")")
+		} else {
+			sb.WriteString(c.sql)
+		}
+		args = append(args, c.args...)
+	}
+	return sb.String(), args
+}
+
+// ToSQL returns the query and its args.
+func (b *Builder) ToSQL() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	if b.table == "" {
+		return "", nil, errors.New("mysql: builder has no table")
+	}
+	table, err := quoteTable(b.table)
+	if err != nil {
+		return "", nil, err
+	}
+	var sb strings.Builder
+	var args []interface{}
+
+	switch b.statement {
+	case selectStatement:
+		sb.WriteString("SELECT ")
+		if len(b.columns) == 0 {
+			sb.WriteString("*")
+		}
+		sb.WriteString(strings.Join(b.columns, ", "))
+		sb.WriteString(" FROM " + table)
+	case insertStatement:
+		if len(b.rows) == 0 {
+			return "", nil, fmt.Errorf("mysql: insert into %s has no values", b.table)
+		}
+		sb.WriteString("INSERT INTO " + table + " (" + strings.Join(b.columns, ", ") + ") VALUES ")
+		for i, row := range b.rows {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString("(" + placeholders(len(row)) + ")")
+			args = append(args, row...)
+		}
+		return sb.String(), args, nil
+	case updateStatement:
+		if len(b.sets) == 0 {
+			return "", nil, fmt.Errorf("mysql: update of %s sets nothing", b.table)
+		}
+		sb.WriteString("UPDATE " + table)
+	case deleteStatement:
+		sb.WriteString("DELETE FROM " + table)
+	}
+
+	for _, j := range b.joins {
+		sb.WriteString(" " + j)
+	}
+	args = append(args, b.joinArgs...)
+	if b.statement == updateStatement {
+		sb.WriteString(" SET " + strings.Join(b.sets, ", "))
+		args = append(args, b.setArgs...)
+	}
+	if len(b.where) > 0 {
+		var where string
+		where, args = renderConditions(b.where, args)
+		sb.WriteString(" WHERE " + where)
+	}
+	if len(b.groupBy) > 0 {
+		sb.WriteString(" GROUP BY " + strings.Join(b.groupBy, ", "))
+	}
+	if len(b.having) > 0 {
+		var having string
+		having, args = renderConditions(b.having, args)
+		sb.WriteString(" HAVING " + having)
+	}
+	if len(b.orderBy) > 0 {
+		sb.WriteString(" ORDER BY " + strings.Join(b.orderBy, ", "))
+	}
+	if b.limit >= 0 {
+		sb.WriteString(" LIMIT " + strconv.Itoa(b.limit))
+		if b.offset > 0 && b.statement == selectStatement {
+			sb.WriteString(" OFFSET " + strconv.Itoa(b.offset))
+		}
+	}
+	if b.forUpdate && b.statement == selectStatement {
+		sb.WriteString(" FOR UPDATE")
+	}
+	return sb.String(), args, nil
+}
+
+var plainName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)?$`)
+
+// quoteColumn quotes a plain or table-qualified column name. Anything else
+// is an error.
+func quoteColumn(column string) (string, error) {
+	name := strings.TrimSpace(column)
+	if !plainName.MatchString(name) {
+		return "", fmt.Errorf("mysql: %q is not a column name", column)
+	}
+	return quoteIdent(name), nil
+}
+
+// quoteSelectColumn quotes a column of a SELECT: a column name with an
+// optional alias, as in "u.name AS author", "*" or "table.*".
+func quoteSelectColumn(column string) (string, error) {
+	column = strings.TrimSpace(column)
+	if column == "*" {
+		return column, nil
+	}
+	if table := strings.TrimSuffix(column, ".*"); table != column && !strings.Contains(table, ".") {
+		quoted, err := quoteColumn(table)
+		if err != nil {
+			return "", fmt.Errorf("mysql: %q is not a column name", column)
+		}
+		return quoted + ".*", nil
+	}
+	name, alias := splitAlias(column)
+	quoted, err := quoteColumn(name)
+	if err != nil {
+		return "", fmt.Errorf("mysql: %q is not a column name", column)
+	}
+	if alias != "" {
+		quoted += " AS " + quoteIdent(alias)
+	}
+	return quoted, nil
+}
+
+// quoteTable quotes a table name with an optional alias, as in "orders o".
+func quoteTable(table string) (string, error) {
+	name, alias := splitAlias(strings.TrimSpace(table))
+	if !plainName.MatchString(name) {
+		return "", fmt.Errorf("mysql: %q is not a table name", table)
+	}
+	if alias != "" {
+		return quoteIdent(name) + " AS " + quoteIdent(alias), nil
+	}
+	return quoteIdent(name), nil
+}
+
+// quoteOrder quotes a column name followed by an optional ASC or DESC.
+func quoteOrder(column string) (string, error) {
+	fields := strings.Fields(column)
+	if len(fields) == 2 {
+		dir := strings.ToUpper(fields[1])
+		if dir == "ASC" || dir == "DESC" {
+			quoted, err := quoteColumn(fields[0])
+			if err != nil {
+				return "", fmt.Errorf("mysql: %q is not a column name", column)
+			}
+			return quoted + " " + dir, nil
+		}
+	}
+	return quoteColumn(column)
+}
+
+// splitAlias splits "name alias" and "name AS alias". Anything else is
+// returned whole as the name.
+func splitAlias(s string) (string, string) {
+	fields := strings.Fields(s)
+	switch {
+	case len(fields) == 2 && isAlias(fields[1]):
+		return fields[0], fields[1]
+	case len(fields) == 3 && strings.EqualFold(fields[1], "AS") && isAlias(fields[2]):
+		return fields[0], fields[2]
+	}
+	return s, ""
+}
+
+func isAlias(s string) bool {
+	return plainName.MatchString(s) && !strings.Contains(s, ".")
+}
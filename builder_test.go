@@ -0,0 +1,111 @@
+package mysql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuilderToSQL(t *testing.T) {
+	tests := []struct {
+		name  string
+		b     *Builder
+		query string
+		args  []interface{}
+	}{
+		{
+			name: "select",
+			b: Select("id", "u.name n", "o.*").SelectExpr("COUNT(*) AS c").From("users u").
+				Join("orders o", "o.user_id = u.id AND o.state = ?", 2).
+				Where("u.status = ?", 1).In("group_id", 3, 4).Or("u.vip = ?", true).
+				Between("age", 18, 30).GroupBy("u.id").Having("COUNT(*) > ?", 1).
+				OrderBy("created_at DESC", "id").Limit(20).Offset(40).ForUpdate(),
+			query: "SELECT `id`, `u`.`name` AS `n`, `o`.*, COUNT(*) AS c FROM `users` AS `u` " +
+				"JOIN `orders` AS `o` ON o.user_id = u.id AND o.state = ? " +
+				"WHERE (u.status = ?) AND (`group_id` IN (?, ?)) OR (u.vip = ?) AND (`age` BETWEEN ? AND ?) " +
+				"GROUP BY `u`.`id` HAVING COUNT(*) > ? ORDER BY `created_at` DESC, `id` LIMIT 20 OFFSET 40 FOR UPDATE",
+			args: []interface{}{2, 1, 3, 4, true, 18, 30, 1},
+		},
+		{
+			name:  "select star",
+			b:     Select().From("users").Where("id = ?", 1),
+			query: "SELECT * FROM `users` WHERE id = ?",
+			args:  []interface{}{1},
+		},
+		{
+			name:  "order by expression",
+			b:     Select("id").From("t").OrderByExpr("FIELD(status, 'open', 'closed')").GroupByExpr("DATE(created_at)"),
+			query: "SELECT `id` FROM `t` GROUP BY DATE(created_at) ORDER BY FIELD(status, 'open', 'closed')",
+		},
+		{
+			name:  "insert rows",
+			b:     InsertInto("t").Columns("a", "b").Values(1, 2).Values(3, 4),
+			query: "INSERT INTO `t` (`a`, `b`) VALUES (?, ?), (?, ?)",
+			args:  []interface{}{1, 2, 3, 4},
+		},
+		{
+			name:  "insert set",
+			b:     InsertInto("db.t").Set("a", 1).Set("b", 2),
+			query: "INSERT INTO `db`.`t` (`a`, `b`) VALUES (?, ?)",
+			args:  []interface{}{1, 2},
+		},
+		{
+			name:  "update",
+			b:     Update("t").Set("a", 1).SetExpr("hits", "`hits` + ?", 1).Where("id = ?", 9),
+			query: "UPDATE `t` SET `a` = ?, `hits` = `hits` + ? WHERE id = ?",
+			args:  []interface{}{1, 1, 9},
+		},
+		{
+			name:  "delete with empty in",
+			b:     DeleteFrom("t").In("id").Limit(1),
+			query: "DELETE FROM `t` WHERE 1 = 0 LIMIT 1",
+		},
+		{
+			name:  "empty not in",
+			b:     DeleteFrom("t").NotIn("id").Where("a = ?", 1),
+			query: "DELETE FROM `t` WHERE a = ?",
+			args:  []interface{}{1},
+		},
+	}
+	for _, tt := range tests {
+		query, args, err := tt.b.ToSQL()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if query != tt.query {
+			t.Errorf("%s:\n got %s\nwant %s", tt.name, query, tt.query)
+		}
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s: args %v, want %v", tt.name, args, tt.args)
+		}
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *Builder
+		err  string
+	}{
+		{"no table", Select("id"), "no table"},
+		{"order by injection", Select("id").From("users").OrderBy("id; DROP TABLE users"), "not a column name"},
+		{"order by direction", Select("id").From("users").OrderBy("id DESC, name"), "not a column name"},
+		{"select expression", Select("COUNT(*)").From("users"), "not a column name"},
+		{"three part column", Select("a.b.c").From("users"), "not a column name"},
+		{"table", Select("id").From("users; DROP TABLE x"), "not a table name"},
+		{"join table", Select("id").From("users").Join("(SELECT 1) x", "1 = 1"), "not a table name"},
+		{"group by", Select("id").From("t").GroupBy("LOWER(name)"), "not a column name"},
+		{"in column", Select("id").From("t").In("id) OR (1", 1), "not a column name"},
+		{"set column", Update("t").Set("a = a + 1, b", 1), "not a column name"},
+		{"insert values", InsertInto("t").Columns("a").Values(1, 2), "1 columns but 2 values"},
+		{"insert nothing", InsertInto("t"), "no values"},
+		{"update nothing", Update("t").Where("id = ?", 1), "sets nothing"},
+	}
+	for _, tt := range tests {
+		_, _, err := tt.b.ToSQL()
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: got error %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
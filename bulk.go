@@ -0,0 +1,245 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type BulkMode int
+
+const (
+	BulkModeInsert BulkMode = iota
+	BulkModeIgnore
+	BulkModeReplace
+)
+
+const (
+	// maxPlaceholders is the limit on ? placeholders in one prepared statement.
+	maxPlaceholders = 65535
+	// defaultMaxPacket is MySQL 5.7's default max_allowed_packet.
+	defaultMaxPacket = 4 << 20
+	// packetOverhead is kept free in every packet for the protocol headers.
+	packetOverhead = 1024
+)
+
+type BulkOptions struct {
+	Mode BulkMode
+	// BatchSize caps the rows per statement. Zero leaves it to MaxPacket and
+	// the placeholder limit.
+	BatchSize int
+	// MaxPacket is the server's max_allowed_packet in bytes, 4MB when zero.
+	MaxPacket int
+}
+
+// IDRange is the auto-increment ids generated by one statement, assuming
+// auto_increment_increment is 1. Rows skipped by INSERT IGNORE can leave
+// gaps in it.
+type IDRange struct {
+	First int64
+	Last  int64
+}
+
+type BulkResult struct {
+	RowsAffected int64
+	IDs          []IDRange
+}
+
+// BulkInsert inserts models with multi-row INSERT statements, split into
+// chunks that stay under max_allowed_packet and the placeholder limit. On a
+// *Mysql the chunks run in their own transaction; on a *Tx they run in it.
+//
+// The auto-increment column is left out when it is zero in every model. In
+// BulkModeInsert the generated ids are then written back into the models.
+// omitempty does not apply, as every row needs the same columns.
+func BulkInsert[T any](ctx context.Context, exec Executor, models []T, opts BulkOptions) (BulkResult, error) {
+	if exec == nil {
+		return BulkResult{}, errNilExecutor
+	}
+	if len(models) == 0 {
+		return BulkResult{}, nil
+	}
+	if m, ok := exec.(*Mysql); ok {
+		tx, err := m.BeginTx()
+		if err != nil {
+			return BulkResult{}, err
+		}
+		result, err := bulkInsert(ctx, tx, reflect.ValueOf(models), opts)
+		if err != nil {
+			tx.ErrorHappen()
+			tx.Close()
+			return BulkResult{}, err
+		}
+		if err := tx.Close(); err != nil {
+			return BulkResult{}, err
+		}
+		return result, nil
+	}
+	return bulkInsert(ctx, exec, reflect.ValueOf(models), opts)
+}
+
+func bulkInsert(ctx context.Context, exec Executor, models reflect.Value, opts BulkOptions) (BulkResult, error) {
+	var result BulkResult
+
+	elemType := models.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return result, fmt.Errorf("mysql: BulkInsert needs structs or pointers to structs, got %s", models.Type())
+	}
+	row := func(i int) (reflect.Value, error) {
+		v := models.Index(i)
+		if isPtr {
+			if v.IsNil() {
+				return v, fmt.Errorf("mysql: BulkInsert model %d is nil", i)
+			}
+			v = v.Elem()
+		}
+		return v, nil
+	}
+
+	mi := getModelInfo(elemType, exec.mysql().NamingStrategy())
+	first, err := row(0)
+	if err != nil {
+		return result, err
+	}
+
+	// hooks, timestamps and validation run once per row before any row is
+	// sent, so a chunk that is cut short never repeats them
+	now := exec.mysql().now()
+	for i := 0; i < models.Len(); i++ {
+		v, err := row(i)
+		if err != nil {
+			return result, err
+		}
+		if err := beforeInsert(exec, v); err != nil {
+			return result, err
+		}
+		if err := mi.fillCreateTimes(v, now); err != nil {
+			return result, err
+		}
+		if err := validate(exec, v); err != nil {
+			return result, err
+		}
+	}
+
+	var auto *fieldInfo
+	if mi.auto != nil {
+		auto = mi.auto
+		for i := 0; i < models.Len(); i++ {
+			v, _ := row(i)
+			if !v.FieldByIndex(mi.auto.Index).IsZero() {
+				auto = nil
+				break
+			}
+		}
+	}
+	var fields []*fieldInfo
+	for _, f := range mi.Fields {
+		if f != auto {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 0 {
+		return result, fmt.Errorf("mysql: %s has no columns to insert", mi.Type)
+	}
+
+	verb := "INSERT INTO "
+	switch opts.Mode {
+	case BulkModeIgnore:
+		verb = "INSERT IGNORE INTO "
+	case BulkModeReplace:
+		verb = "REPLACE INTO "
+	}
+	cols, _ := fieldColumns(first, fields)
+	head := verb + quoteIdent(mi.tableName(first)) + " (" + strings.Join(cols, ", ") + ") VALUES "
+	rowSQL := "(" + placeholders(len(fields)) + ")"
+
+	maxPacket := opts.MaxPacket
+	if maxPacket <= 0 {
+		maxPacket = defaultMaxPacket
+	}
+	maxRows := maxPlaceholders / len(fields)
+	if opts.BatchSize > 0 && opts.BatchSize < maxRows {
+		maxRows = opts.BatchSize
+	}
+
+	start := 0
+	for start < models.Len() {
+		size := len(head)
+		var args []interface{}
+		end := start
+		for end < models.Len() && end-start < maxRows {
+			v, _ := row(end)
+			rowSize := len(rowSQL) + 2
+			rowArgs := make([]interface{}, len(fields))
+			for i, f := range fields {
+				fv := v.FieldByIndex(f.Index)
+				// a zero time would be stored as '0000-00-00', so a row
+				// that is not deleted sends NULL
+				if f == mi.softDelete && fv.IsZero() {
+					rowArgs[i] = nil
+				} else {
+					rowArgs[i] = fv.Interface()
+				}
+				rowSize += argSize(rowArgs[i])
+			}
+			if end > start && size+rowSize > maxPacket-packetOverhead {
+				break
+			}
+			size += rowSize
+			args = append(args, rowArgs...)
+			end++
+		}
+
+		query := head + strings.TrimSuffix(strings.Repeat(rowSQL+", ", end-start), ", ")
+		res, err := exec.ExecContext(ctx, query, args...)
+		if err != nil {
+			return result, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return result, err
+		}
+		result.RowsAffected += n
+
+		if auto != nil {
+			id, err := res.LastInsertId()
+			if err != nil {
+				return result, err
+			}
+			result.IDs = append(result.IDs, IDRange{First: id, Last: id + int64(end-start) - 1})
+			if opts.Mode == BulkModeInsert {
+				for i := start; i < end; i++ {
+					v, _ := row(i)
+					if err := setFieldValue(v.FieldByIndex(auto.Index), id+int64(i-start), exec.mysql().Location()); err != nil {
+						return result, err
+					}
+				}
+			}
+		}
+		for i := start; i < end; i++ {
+			v, _ := row(i)
+			if err := afterInsert(exec, v); err != nil {
+				return result, err
+			}
+		}
+		start = end
+	}
+	return result, nil
+}
+
+// argSize estimates the bytes an argument takes in the packet.
+func argSize(arg interface{}) int {
+	switch v := arg.(type) {
+	case string:
+		return len(v) + 9
+	case []byte:
+		return len(v) + 9
+	default:
+		return 16
+	}
+}
@@ -0,0 +1,110 @@
+package mysql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// modelType returns the struct type of a model given as a struct, a pointer
+// to one, or a slice or pointer to slice of them.
+func modelType(model interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(model)
+	if t == nil {
+		return nil, fmt.Errorf("mysql: nil model")
+	}
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("mysql: model must be a struct, got %T", model)
+	}
+	return t, nil
+}
+
+// columnList returns the quoted columns of mi, qualified with alias if set.
+func (mi *modelInfo) columnList(alias string) []string {
+	cols := make([]string, len(mi.Fields))
+	for i, f := range mi.Fields {
+		cols[i] = quoteIdent(f.Column)
+		if alias != "" {
+			cols[i] = quoteIdent(alias) + "." + cols[i]
+		}
+	}
+	return cols
+}
+
+func selectColumns(model interface{}, naming NamingStrategy, alias []string) (string, error) {
+	t, err := modelType(model)
+	if err != nil {
+		return "", err
+	}
+	a := ""
+	if len(alias) > 0 {
+		a = alias[0]
+	}
+	return strings.Join(getModelInfo(t, naming).columnList(a), ", "), nil
+}
+
+// SelectColumns returns the column list of a model for a SELECT, such as
+// "`id`, `user_id`, `total`", or "`o`.`id`, `o`.`user_id`, `o`.`total`" with
+// an alias for joins. A projection struct mapping only some columns gets
+// only those. model is a struct, a pointer to one or a slice of them; any
+// other type is an error.
+//
+//	cols, err := SelectColumns(OrderSummary{}, "o")
+//	query := "SELECT " + cols + " FROM orders o JOIN users u ON u.id = o.user_id"
+func SelectColumns(model interface{}, alias ...string) (string, error) {
+	return selectColumns(model, DefaultNaming, alias)
+}
+
+// SelectColumns is SelectColumns with the naming strategy of m.
+func (m *Mysql) SelectColumns(model interface{}, alias ...string) (string, error) {
+	return selectColumns(model, m.NamingStrategy(), alias)
+}
+
+func (tx *Tx) SelectColumns(model interface{}, alias ...string) (string, error) {
+	return selectColumns(model, tx.db.NamingStrategy(), alias)
+}
+
+// ColumnsOf adds the columns of a model to a SELECT, qualified with alias if
+// set, named by the naming strategy of the builder. An error is reported by
+// ToSQL.
+func (b *Builder) ColumnsOf(model interface{}, alias string) *Builder {
+	t, err := modelType(model)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	b.columns = append(b.columns, getModelInfo(t, b.namingStrategy()).columnList(alias)...)
+	return b
+}
+
+// SelectFrom starts a SELECT of the columns of a model from its table, named
+// by DefaultNaming. A projection struct without a TableName method needs
+// From to name the table.
+func SelectFrom(model interface{}) *Builder {
+	return selectFrom(model, DefaultNaming)
+}
+
+// SelectFrom is SelectFrom with the naming strategy of m, which the builder
+// keeps for ColumnsOf and Filter.
+func (m *Mysql) SelectFrom(model interface{}) *Builder {
+	return selectFrom(model, m.NamingStrategy())
+}
+
+func (tx *Tx) SelectFrom(model interface{}) *Builder {
+	return selectFrom(model, tx.db.NamingStrategy())
+}
+
+func selectFrom(model interface{}, naming NamingStrategy) *Builder {
+	b := Select().WithNaming(naming)
+	t, err := modelType(model)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	mi := getModelInfo(t, naming)
+	b.columns = mi.columnList("")
+	return b.From(mi.tableName(reflect.New(t).Elem()))
+}
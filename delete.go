@@ -0,0 +1,70 @@
+package mysql
+
+import (
+	"context"
+)
+
+func deleteModel(ctx context.Context, exec Executor, model interface{}, opts []ModelOption) (int64, error) {
+	v, err := modelValue(model)
+	if err != nil {
+		return -1, err
+	}
+	mi := getModelInfo(v.Type(), exec.mysql().NamingStrategy())
+	where, args, err := mi.pkWhere(v)
+	if err != nil {
+		return -1, err
+	}
+	o, err := newModelOptions(opts)
+	if err != nil {
+		return -1, err
+	}
+	table := quoteIdent(mi.tableName(v))
+	if err := beforeDelete(exec, v); err != nil {
+		return -1, err
+	}
+
+	f := mi.softDelete
+	if f == nil || o.hardDelete {
+		res, err := exec.ExecContext(ctx, "DELETE FROM "+table+" WHERE "+where, args...)
+		if err != nil {
+			return -1, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return -1, err
+		}
+		return n, afterDelete(exec, v)
+	}
+
+	now := exec.mysql().now()
+	var deleted interface{} = now
+	if isIntegerKind(indirectType(f.Type).Kind()) {
+		deleted = now.Unix()
+	}
+	query := "UPDATE " + table + " SET " + quoteIdent(f.Column) + " = ? WHERE " + where + " AND " + f.notDeleted()
+	res, err := exec.ExecContext(ctx, query, append([]interface{}{deleted}, args...)...)
+	if err != nil {
+		return -1, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+	if n > 0 {
+		if err := setFieldValue(v.FieldByIndex(f.Index), deleted, exec.mysql().Location()); err != nil {
+			return n, err
+		}
+	}
+	return n, afterDelete(exec, v)
+}
+
+// DeleteModel deletes the row identified by the model's pk fields. If the
+// model has a softDelete field, the field is set to the current time instead;
+// pass HardDelete to remove the row anyway.
+func (m *Mysql) DeleteModel(model interface{}, opts ...ModelOption) (int64, error) {
+	return deleteModel(context.Background(), m, model, opts)
+}
+
+func (tx *Tx) DeleteModel(model interface{}, opts ...ModelOption) (int64, error) {
+	return deleteModel(context.Background(), tx, model, opts)
+}
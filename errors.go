@@ -0,0 +1,139 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	driver "github.com/go-sql-driver/mysql"
+)
+
+// ErrNotFound is returned when a single-row query matches nothing. It also
+// matches sql.ErrNoRows with errors.Is.
+var ErrNotFound = fmt.Errorf("mysql: not found: %w", sql.ErrNoRows)
+
+var (
+	ErrDuplicateKey = errors.New("mysql: duplicate key")
+	ErrDeadlock     = errors.New("mysql: deadlock")
+	ErrLockTimeout  = errors.New("mysql: lock wait timeout")
+	ErrForeignKey   = errors.New("mysql: foreign key constraint")
+	ErrReadOnly     = errors.New("mysql: read only")
+)
+
+var errorKinds = map[uint16]error{
+	1062: ErrDuplicateKey,
+	1213: ErrDeadlock,
+	1205: ErrLockTimeout,
+	1216: ErrForeignKey,
+	1217: ErrForeignKey,
+	1451: ErrForeignKey,
+	1452: ErrForeignKey,
+	1290: ErrReadOnly,
+	1792: ErrReadOnly,
+	1836: ErrReadOnly,
+}
+
+// Error wraps a *mysql.MySQLError whose number has a sentinel error, so
+// errors.Is(err, ErrDuplicateKey) works. For duplicate keys, Entry and Key
+// hold the duplicated value and the name of the violated index.
+type Error struct {
+	Number uint16
+	Entry  string
+	Key    string
+	kind   error
+	err    *driver.MySQLError
+}
+
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// DuplicateKey returns the name of the violated index when err is a
+// duplicate key error.
+func DuplicateKey(err error) (string, bool) {
+	var e *Error
+	if errors.As(err, &e) && e.kind == ErrDuplicateKey {
+		return e.Key, true
+	}
+	return "", false
+}
+
+// parseDuplicate parses "Duplicate entry 'x' for key 'idx'". MySQL 8 reports
+// the key as 'table.idx'.
+func parseDuplicate(message string) (entry string, key string) {
+	const prefix = "Duplicate entry '"
+	const middle = "' for key '"
+	if !strings.HasPrefix(message, prefix) {
+		return "", ""
+	}
+	i := strings.LastIndex(message, middle)
+	if i < len(prefix) {
+		return "", ""
+	}
+	entry = message[len(prefix):i]
+	key = strings.TrimSuffix(message[i+len(middle):], "'")
+	if j := strings.LastIndex(key, "."); j >= 0 {
+		key = key[j+1:]
+	}
+	return entry, key
+}
+
+func convertError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var me *driver.MySQLError
+	if !errors.As(err, &me) {
+		return err
+	}
+	kind, ok := errorKinds[me.Number]
+	if !ok {
+		return err
+	}
+	e := &Error{Number: me.Number, kind: kind, err: me}
+	if kind == ErrDuplicateKey {
+		e.Entry, e.Key = parseDuplicate(me.Message)
+	}
+	return e
+}
+
+// SetNotFoundError makes QueryForMap, QueryForModel and QueryOne return
+// ErrNotFound instead of an empty result when nothing matches.
+func (m *Mysql) SetNotFoundError(enable bool) {
+	m.notFoundError = enable
+}
+
+// ErrorMapper translates database errors into application errors, for
+// example to log them and return an error code. It receives the error after
+// the built-in conversion, so errors.Is(err, ErrDuplicateKey) already works.
+type ErrorMapper func(err error) error
+
+// SetErrorMapper installs mapper for every Mysql and Tx method, including
+// the errors from committing or rolling back in Tx.Close.
+func (m *Mysql) SetErrorMapper(mapper ErrorMapper) {
+	m.errorMapper = mapper
+}
+
+func (m *Mysql) convertError(err error) error {
+	err = convertError(err)
+	if err != nil && m != nil && m.errorMapper != nil {
+		err = m.errorMapper(err)
+	}
+	return err
+}
+
+func (m *Mysql) notFound() error {
+	if m != nil && m.notFoundError {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package mysql
+
+import "testing"
+
+func TestParseDuplicate(t *testing.T) {
+	tests := []struct {
+		message string
+		entry   string
+		key     string
+	}{
+		{"Duplicate entry 'bob' for key 'name'", "bob", "name"},
+		{"Duplicate entry 'bob' for key 'users.uniq_name'", "bob", "uniq_name"},
+		{"Duplicate entry '7-bob' for key 'PRIMARY'", "7-bob", "PRIMARY"},
+		{"Duplicate entry 'it's' for key 'name'", "it's", "name"},
+		{"Duplicate entry 'x' for key 'y' for key 'users.idx'", "x' for key 'y", "idx"},
+		{"Duplicate entry '' for key 'name'", "", "name"},
+		{"Table 'users' doesn't exist", "", ""},
+		{"Duplicate entry 'bob'", "", ""},
+	}
+	for _, tt := range tests {
+		entry, key := parseDuplicate(tt.message)
+		if entry != tt.entry || key != tt.key {
+			t.Errorf("parseDuplicate(%q) = %q, %q, want %q, %q", tt.message, entry, key, tt.entry, tt.key)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Executor is implemented by *Mysql and *Tx, so the typed query functions
+// and model APIs run the same way inside and outside a transaction.
+type Executor interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	mysql() *Mysql
+}
+
+func (m *Mysql) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	query, args, err := m.bind(query, args)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := m.conn.QueryContext(ctx, query, args...)
+	return rows, m.convertError(err)
+}
+
+func (m *Mysql) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	query, args, err := m.bind(query, args)
+	if err != nil {
+		return nil, err
+	}
+	res, err := m.conn.ExecContext(ctx, query, args...)
+	return res, m.convertError(err)
+}
+
+func (m *Mysql) mysql() *Mysql {
+	return m
+}
+
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	query, args, err := tx.db.bind(query, args)
+	if err != nil {
+		tx.ErrorHappen()
+		return nil, err
+	}
+	rows, err := tx.Tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		tx.ErrorHappen()
+	}
+	return rows, tx.db.convertError(err)
+}
+
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	query, args, err := tx.db.bind(query, args)
+	if err != nil {
+		tx.ErrorHappen()
+		return nil, err
+	}
+	res, err := tx.Tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		tx.ErrorHappen()
+	}
+	return res, tx.db.convertError(err)
+}
+
+func (tx *Tx) mysql() *Mysql {
+	return tx.db
+}
@@ -0,0 +1,179 @@
+package mysql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// filterOps maps the operators of a `filter` tag to SQL.
+var filterOps = map[string]string{
+	"eq":    " = ?",
+	"ne":    " <> ?",
+	"gt":    " > ?",
+	"gte":   " >= ?",
+	"lt":    " < ?",
+	"lte":   " <= ?",
+	"like":  " LIKE ?",
+	"in":    " IN",
+	"notin": " NOT IN",
+}
+
+type filterField struct {
+	Index  []int
+	Column string
+	Op     string
+}
+
+var filterCache sync.Map
+
+func getFilterFields(t reflect.Type, naming NamingStrategy) ([]filterField, error) {
+	key := newModelKey(t, naming)
+	if cached, ok := filterCache.Load(key); ok {
+		return cached.([]filterField), nil
+	}
+	fields, err := parseFilterFields(t, naming, nil)
+	if err != nil {
+		return nil, err
+	}
+	filterCache.Store(key, fields)
+	return fields, nil
+}
+
+func parseFilterFields(t reflect.Type, naming NamingStrategy, parent []int) ([]filterField, error) {
+	var fields []filterField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		index := append(append([]int{}, parent...), i)
+		tag, ok := sf.Tag.Lookup("filter")
+		if !ok {
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				embedded, err := parseFilterFields(sf.Type, naming, index)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, embedded...)
+			}
+			continue
+		}
+		if tag == "-" || sf.PkgPath != "" {
+			continue
+		}
+		column, op, _ := strings.Cut(tag, ",")
+		column = strings.TrimSpace(column)
+		op = strings.ToLower(strings.TrimSpace(op))
+		if column == "" {
+			column = naming.ColumnName(sf.Name)
+		}
+		if op == "" {
+			op = "eq"
+		}
+		if _, ok := filterOps[op]; !ok {
+			return nil, fmt.Errorf("mysql: filter %s.%s has unknown operator %q", t, sf.Name, op)
+		}
+		fields = append(fields, filterField{Index: index, Column: column, Op: op})
+	}
+	return fields, nil
+}
+
+// escapeLike escapes the wildcards of s for a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Filter compiles a filter struct into WHERE conditions joined with AND, and
+// their args. Fields are tagged `filter:"column,op"`, where op is one of eq
+// (the default), ne, gt, gte, lt, lte, like, in and notin:
+//
+//	type OrderFilter struct {
+//		Name   string    `filter:"name,like"`
+//		From   time.Time `filter:"created_at,gte"`
+//		Status []int     `filter:"status,in"`
+//		Paid   *bool     `filter:"paid"`
+//	}
+//
+// Zero, nil and empty fields are skipped, so a pointer is needed to filter
+// on a zero value. like matches the value anywhere in the column. Fields
+// without a filter tag are ignored. The result is empty when every field is
+// skipped; it can be passed to Builder.Where or appended to a raw query.
+// A tag without a column takes the field name through DefaultNaming.
+func Filter(filter interface{}) (string, []interface{}, error) {
+	return compileFilter(filter, DefaultNaming)
+}
+
+// Filter is Filter with the naming strategy of m for untagged columns.
+func (m *Mysql) Filter(filter interface{}) (string, []interface{}, error) {
+	return compileFilter(filter, m.NamingStrategy())
+}
+
+func (tx *Tx) Filter(filter interface{}) (string, []interface{}, error) {
+	return compileFilter(filter, tx.db.NamingStrategy())
+}
+
+func compileFilter(filter interface{}, naming NamingStrategy) (string, []interface{}, error) {
+	v := reflect.ValueOf(filter)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", nil, fmt.Errorf("mysql: filter must be a struct, got %T", filter)
+	}
+	fields, err := getFilterFields(v.Type(), naming)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var conds []string
+	var args []interface{}
+	for _, f := range fields {
+		fv := v.FieldByIndex(f.Index)
+		if fv.IsZero() {
+			continue
+		}
+		if fv.Kind() == reflect.Ptr {
+			fv = fv.Elem()
+		}
+		col, err := quoteColumn(f.Column)
+		if err != nil {
+			return "", nil, err
+		}
+		switch f.Op {
+		case "in", "notin":
+			if fv.Kind() != reflect.Slice && fv.Kind() != reflect.Array {
+				return "", nil, fmt.Errorf("mysql: filter %s %s needs a slice, got %s", f.Column, f.Op, fv.Type())
+			}
+			if fv.Len() == 0 {
+				continue
+			}
+			conds = append(conds, col+filterOps[f.Op]+" ("+placeholders(fv.Len())+")")
+			for i := 0; i < fv.Len(); i++ {
+				args = append(args, fv.Index(i).Interface())
+			}
+		case "like":
+			conds = append(conds, col+filterOps[f.Op])
+			args = append(args, "%"+escapeLike(fmt.Sprint(fv.Interface()))+"%")
+		default:
+			conds = append(conds, col+filterOps[f.Op])
+			args = append(args, fv.Interface())
+		}
+	}
+	return strings.Join(conds, " AND "), args, nil
+}
+
+// Filter adds the conditions of a filter struct, see Filter, named by the
+// naming strategy of the builder.
+func (b *Builder) Filter(filter interface{}) *Builder {
+	where, args, err := compileFilter(filter, b.namingStrategy())
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	if where != "" {
+		b.Where(where, args...)
+	}
+	return b
+}
@@ -0,0 +1,118 @@
+package mysql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type baseFilter struct {
+	Tenant int `filter:"tenant_id"`
+}
+
+type orderFilter struct {
+	baseFilter
+	Name      string    `filter:"o.name,like"`
+	CreatedAt time.Time `filter:",gte"`
+	Status    []int     `filter:"status,in"`
+	Excluded  []int     `filter:"id,notin"`
+	Paid      *bool     `filter:"paid"`
+	MinTotal  float64   `filter:"total,gt"`
+	Page      int
+}
+
+func TestFilter(t *testing.T) {
+	paid := false
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		filter interface{}
+		naming NamingStrategy
+		where  string
+		args   []interface{}
+	}{
+		{
+			name:   "all operators",
+			filter: &orderFilter{baseFilter: baseFilter{7}, Name: "50%_x", Status: []int{1, 2}, Excluded: []int{9}, Paid: &paid, MinTotal: 1.5, Page: 3},
+			naming: DefaultNaming,
+			where:  "`tenant_id` = ? AND `o`.`name` LIKE ? AND `status` IN (?, ?) AND `id` NOT IN (?) AND `paid` = ? AND `total` > ?",
+			args:   []interface{}{7, `%50\%\_x%`, 1, 2, 9, false, 1.5},
+		},
+		{
+			name:   "zero fields skipped",
+			filter: orderFilter{Status: []int{}},
+			naming: DefaultNaming,
+		},
+		{
+			name:   "nil pointer",
+			filter: (*orderFilter)(nil),
+			naming: DefaultNaming,
+		},
+		{
+			name:   "default naming",
+			filter: orderFilter{CreatedAt: from},
+			naming: DefaultNaming,
+			where:  "`CreatedAt` >= ?",
+			args:   []interface{}{from},
+		},
+		{
+			name:   "snake naming",
+			filter: orderFilter{CreatedAt: from},
+			naming: SnakeNaming{},
+			where:  "`created_at` >= ?",
+			args:   []interface{}{from},
+		},
+	}
+	for _, tt := range tests {
+		where, args, err := compileFilter(tt.filter, tt.naming)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if where != tt.where {
+			t.Errorf("%s: where %q, want %q", tt.name, where, tt.where)
+		}
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s: args %v, want %v", tt.name, args, tt.args)
+		}
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	type unknownOp struct {
+		X int `filter:"x,between"`
+	}
+	type notSlice struct {
+		X int `filter:"x,in"`
+	}
+	type badColumn struct {
+		X int `filter:"x = 1 OR 1"`
+	}
+	tests := []struct {
+		name   string
+		filter interface{}
+		err    string
+	}{
+		{"unknown operator", unknownOp{X: 1}, "unknown operator"},
+		{"in needs a slice", notSlice{X: 1}, "needs a slice"},
+		{"bad column", badColumn{X: 1}, "not a column name"},
+		{"not a struct", 3, "must be a struct"},
+	}
+	for _, tt := range tests {
+		_, _, err := Filter(tt.filter)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: got error %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestBuilderFilter(t *testing.T) {
+	query, args, err := Select().From("orders o").Filter(orderFilter{Status: []int{3}}).ToSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "SELECT * FROM `orders` AS `o` WHERE `status` IN (?)" || !reflect.DeepEqual(args, []interface{}{3}) {
+		t.Fatal(query, args)
+	}
+}
@@ -0,0 +1,277 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ModelOption changes how the model APIs build their statements.
+type ModelOption func(*modelOptions)
+
+type modelOptions struct {
+	where      []string
+	args       []interface{}
+	unscoped   bool
+	hardDelete bool
+	preload    []string
+	order      []string
+	limit      int
+	offset     int
+	err        error
+}
+
+func newModelOptions(opts []ModelOption) (*modelOptions, error) {
+	o := &modelOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o, o.err
+}
+
+// Where adds a condition, ANDed with the others.
+func Where(cond string, args ...interface{}) ModelOption {
+	return func(o *modelOptions) {
+		o.where = append(o.where, "("+cond+")")
+		o.args = append(o.args, args...)
+	}
+}
+
+// OrderBy sorts the rows by columns, each with an optional ASC or DESC.
+// Anything but a column name is an error; see OrderByExpr.
+func OrderBy(columns ...string) ModelOption {
+	return func(o *modelOptions) {
+		for _, c := range columns {
+			quoted, err := quoteOrder(c)
+			if err != nil {
+				if o.err == nil {
+					o.err = err
+				}
+				continue
+			}
+			o.order = append(o.order, quoted)
+		}
+	}
+}
+
+// OrderByExpr sorts the rows by expressions, written as given.
+func OrderByExpr(exprs ...string) ModelOption {
+	return func(o *modelOptions) {
+		o.order = append(o.order, exprs...)
+	}
+}
+
+// Limit returns at most limit rows, skipping offset rows first.
+func Limit(limit int, offset int) ModelOption {
+	return func(o *modelOptions) {
+		o.limit = limit
+		o.offset = offset
+	}
+}
+
+// Unscoped includes soft-deleted rows.
+func Unscoped() ModelOption {
+	return func(o *modelOptions) {
+		o.unscoped = true
+	}
+}
+
+// HardDelete makes DeleteModel remove the row even if the model has a
+// softDelete field.
+func HardDelete() ModelOption {
+	return func(o *modelOptions) {
+		o.hardDelete = true
+	}
+}
+
+// notDeleted is the condition matching rows that are not soft-deleted.
+// Integer columns hold a Unix time and are 0 until deleted; any other
+// column is NULL until deleted.
+func (f *fieldInfo) notDeleted() string {
+	if isIntegerKind(indirectType(f.Type).Kind()) {
+		return quoteIdent(f.Column) + " = 0"
+	}
+	return quoteIdent(f.Column) + " IS NULL"
+}
+
+// conditions returns the WHERE conditions of o plus the soft delete scope.
+func (mi *modelInfo) conditions(o *modelOptions) []string {
+	conds := append([]string{}, o.where...)
+	if mi.softDelete != nil && !o.unscoped {
+		conds = append(conds, mi.softDelete.notDeleted())
+	}
+	return conds
+}
+
+func (mi *modelInfo) selectSQL(table string, o *modelOptions) (string, []interface{}) {
+	columns := "*"
+	if len(mi.Fields) > 0 {
+		columns = strings.Join(mi.columnList(""), ", ")
+	}
+	query := "SELECT " + columns + " FROM " + quoteIdent(table)
+	if conds := mi.conditions(o); len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	if len(o.order) > 0 {
+		query += " ORDER BY " + strings.Join(o.order, ", ")
+	}
+	if o.limit > 0 {
+		query += " LIMIT " + strconv.Itoa(o.limit)
+		if o.offset > 0 {
+			query += " OFFSET " + strconv.Itoa(o.offset)
+		}
+	}
+	return query, o.args
+}
+
+func findModels(ctx context.Context, exec Executor, models interface{}, opts []ModelOption) error {
+	sliceValue, elemType, err := modelSlice(models)
+	if err != nil {
+		return err
+	}
+	mi := getModelInfo(elemType, exec.mysql().NamingStrategy())
+	o, err := newModelOptions(opts)
+	if err != nil {
+		return err
+	}
+	query, args := mi.selectSQL(mi.tableName(reflect.New(elemType).Elem()), o)
+
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	start := sliceValue.Len()
+	if err := appendModels(rows, sliceValue, mi, exec); err != nil {
+		return err
+	}
+	rows.Close()
+	return preload(ctx, exec, elemType, sliceElements(sliceValue.Slice(start, sliceValue.Len())), o.preload)
+}
+
+func findModel(ctx context.Context, exec Executor, model interface{}, opts []ModelOption) (bool, error) {
+	v, err := modelValue(model)
+	if err != nil {
+		return false, err
+	}
+	mi := getModelInfo(v.Type(), exec.mysql().NamingStrategy())
+	o, err := newModelOptions(opts)
+	if err != nil {
+		return false, err
+	}
+	o.limit = 1
+	query, args := mi.selectSQL(mi.tableName(v), o)
+
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	scanner, err := newModelScanner(rows, mi, exec)
+	if err != nil {
+		return false, exec.mysql().convertError(err)
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return false, exec.mysql().convertError(err)
+		}
+		return false, exec.mysql().notFound()
+	}
+	if err := scanner.scan(rows, v); err != nil {
+		return false, err
+	}
+	rows.Close()
+	return true, preload(ctx, exec, v.Type(), []reflect.Value{v}, o.preload)
+}
+
+func getModel(ctx context.Context, exec Executor, model interface{}, pk interface{}, opts []ModelOption) (bool, error) {
+	v, err := modelValue(model)
+	if err != nil {
+		return false, err
+	}
+	mi := getModelInfo(v.Type(), exec.mysql().NamingStrategy())
+	where, args, err := mi.pkCondition(pk)
+	if err != nil {
+		return false, err
+	}
+	return findModel(ctx, exec, model, append([]ModelOption{Where(where, args...)}, opts...))
+}
+
+// getModels appends the rows with primary keys pks to models, querying in
+// chunks that stay under the placeholder limit.
+func getModels(ctx context.Context, exec Executor, models interface{}, pks []interface{}, opts []ModelOption) error {
+	_, elemType, err := modelSlice(models)
+	if err != nil {
+		return err
+	}
+	mi := getModelInfo(elemType, exec.mysql().NamingStrategy())
+	if len(mi.pks) == 0 {
+		return fmt.Errorf("mysql: %s has no field tagged pk", mi.Type)
+	}
+	keys := make([][]interface{}, len(pks))
+	for i, pk := range pks {
+		if keys[i], err = mi.keyValues(pk); err != nil {
+			return err
+		}
+	}
+	size := (maxPlaceholders - 1) / len(mi.pks)
+	for start := 0; start < len(keys); start += size {
+		end := start + size
+		if end > len(keys) {
+			end = len(keys)
+		}
+		where, args := mi.pkIn(keys[start:end])
+		if err := findModels(ctx, exec, models, append([]ModelOption{Where(where, args...)}, opts...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindModels loads the rows of the models' table into models, a pointer to
+// a slice of structs or pointers to structs. Soft-deleted rows are left out
+// unless Unscoped is given.
+func (m *Mysql) FindModels(models interface{}, opts ...ModelOption) error {
+	return findModels(context.Background(), m, models, opts)
+}
+
+// FindModel loads the first matching row into model.
+func (m *Mysql) FindModel(model interface{}, opts ...ModelOption) (bool, error) {
+	return findModel(context.Background(), m, model, opts)
+}
+
+// GetModel loads the row with primary key pk into model. A composite key
+// is passed as a Key, or []interface{}, in the order of the pk fields.
+func (m *Mysql) GetModel(model interface{}, pk interface{}, opts ...ModelOption) (bool, error) {
+	return getModel(context.Background(), m, model, pk, opts)
+}
+
+// GetModels appends the rows with the primary keys pks to models, in no
+// particular order, with one query per 65535 placeholders:
+//
+//	err := m.GetModels(&links, []interface{}{Key{1, 2}, Key{1, 3}})
+//
+// runs WHERE (`user_id`, `group_id`) IN ((?, ?), (?, ?)).
+func (m *Mysql) GetModels(models interface{}, pks []interface{}, opts ...ModelOption) error {
+	return getModels(context.Background(), m, models, pks, opts)
+}
+
+func (tx *Tx) FindModels(models interface{}, opts ...ModelOption) error {
+	return findModels(context.Background(), tx, models, opts)
+}
+
+func (tx *Tx) FindModel(model interface{}, opts ...ModelOption) (bool, error) {
+	return findModel(context.Background(), tx, model, opts)
+}
+
+func (tx *Tx) GetModel(model interface{}, pk interface{}, opts ...ModelOption) (bool, error) {
+	return getModel(context.Background(), tx, model, pk, opts)
+}
+
+func (tx *Tx) GetModels(models interface{}, pks []interface{}, opts ...ModelOption) error {
+	return getModels(context.Background(), tx, models, pks, opts)
+}
@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"reflect"
+)
+
+// Models can implement these hooks to take part in the model APIs. Each hook
+// gets the Executor the operation runs on, so it can query in the same
+// transaction. A hook error aborts the operation and, on a Tx, marks it
+// for rollback.
+//
+// Inserts run BeforeInsert, then fill the timestamps, then Validate, and
+// updates the same with BeforeUpdate. Upserts run the insert hooks, then
+// AfterInsert or AfterUpdate depending on what the statement did. AfterFind
+// runs for every row decoded into a model, including by QueryForModel and
+// QueryForModelSlice.
+type BeforeInserter interface {
+	BeforeInsert(exec Executor) error
+}
+
+type AfterInserter interface {
+	AfterInsert(exec Executor) error
+}
+
+type BeforeUpdater interface {
+	BeforeUpdate(exec Executor) error
+}
+
+type AfterUpdater interface {
+	AfterUpdate(exec Executor) error
+}
+
+type BeforeDeleter interface {
+	BeforeDelete(exec Executor) error
+}
+
+type AfterDeleter interface {
+	AfterDelete(exec Executor) error
+}
+
+type AfterFinder interface {
+	AfterFind(exec Executor) error
+}
+
+type Validator interface {
+	Validate() error
+}
+
+// hookError marks a Tx for rollback and returns err.
+func hookError(exec Executor, err error) error {
+	if err != nil {
+		if tx, ok := exec.(*Tx); ok {
+			tx.ErrorHappen()
+		}
+	}
+	return err
+}
+
+func hookModel(v reflect.Value) interface{} {
+	if v.CanAddr() {
+		return v.Addr().Interface()
+	}
+	return v.Interface()
+}
+
+func beforeInsert(exec Executor, v reflect.Value) error {
+	if h, ok := hookModel(v).(BeforeInserter); ok {
+		return hookError(exec, h.BeforeInsert(exec))
+	}
+	return nil
+}
+
+func afterInsert(exec Executor, v reflect.Value) error {
+	if h, ok := hookModel(v).(AfterInserter); ok {
+		return hookError(exec, h.AfterInsert(exec))
+	}
+	return nil
+}
+
+func beforeUpdate(exec Executor, v reflect.Value) error {
+	if h, ok := hookModel(v).(BeforeUpdater); ok {
+		return hookError(exec, h.BeforeUpdate(exec))
+	}
+	return nil
+}
+
+func afterUpdate(exec Executor, v reflect.Value) error {
+	if h, ok := hookModel(v).(AfterUpdater); ok {
+		return hookError(exec, h.AfterUpdate(exec))
+	}
+	return nil
+}
+
+func beforeDelete(exec Executor, v reflect.Value) error {
+	if h, ok := hookModel(v).(BeforeDeleter); ok {
+		return hookError(exec, h.BeforeDelete(exec))
+	}
+	return nil
+}
+
+func afterDelete(exec Executor, v reflect.Value) error {
+	if h, ok := hookModel(v).(AfterDeleter); ok {
+		return hookError(exec, h.AfterDelete(exec))
+	}
+	return nil
+}
+
+func afterFind(exec Executor, v reflect.Value) error {
+	if h, ok := hookModel(v).(AfterFinder); ok {
+		return hookError(exec, h.AfterFind(exec))
+	}
+	return nil
+}
+
+func validate(exec Executor, v reflect.Value) error {
+	if h, ok := hookModel(v).(Validator); ok {
+		return hookError(exec, h.Validate())
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrIdentNotAllowed is returned for a name missing from an Allowlist.
+var ErrIdentNotAllowed = errors.New("mysql: identifier not allowed")
+
+// CheckIdent reports whether name is a name MySQL accepts: 1 to 64
+// characters, no NUL and no trailing space.
+func CheckIdent(name string) error {
+	if name == "" || len(name) > 64 || strings.ContainsRune(name, 0) || strings.HasSuffix(name, " ") {
+		return fmt.Errorf("mysql: invalid identifier %q", name)
+	}
+	return nil
+}
+
+// QuoteIdent checks name and quotes it with backticks as one identifier,
+// doubling embedded backticks, so that it can be put into a query whatever
+// it holds. A dot is part of the name: "mysql.user" becomes `mysql.user`,
+// never a table of another schema.
+func QuoteIdent(name string) (string, error) {
+	if err := CheckIdent(name); err != nil {
+		return "", err
+	}
+	return quoteName(name), nil
+}
+
+// QuoteQualifiedIdent is QuoteIdent for a trusted name qualified as
+// db.table or table.column, quoting every part.
+func QuoteQualifiedIdent(name string) (string, error) {
+	for _, part := range strings.Split(name, ".") {
+		if CheckIdent(part) != nil {
+			return "", fmt.Errorf("mysql: invalid identifier %q", name)
+		}
+	}
+	return quoteIdent(name), nil
+}
+
+// quoteName quotes name as a single identifier.
+func quoteName(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+// ShardTable returns the table of a sharded set, base_N, that id falls in
+// with GetTableNumber.
+func ShardTable(base string, id string, n uint32) string {
+	return base + "_" + strconv.FormatUint(uint64(GetTableNumber(id, n)), 10)
+}
+
+// ExpandIdents replaces the {name} placeholders of query with the quoted
+// identifiers in names:
+//
+//	query, err := ExpandIdents("SELECT * FROM {table} ORDER BY {sort} DESC",
+//		map[string]string{"table": ShardTable("orders", uid, 16), "sort": sort})
+//
+// Every placeholder needs a name and every name must be used. Each name is
+// quoted as one identifier, see QuoteIdent; qualified names need an
+// Allowlist. Braces inside quotes and comments are left alone.
+func ExpandIdents(query string, names map[string]string) (string, error) {
+	return expandIdents(query, names, nil)
+}
+
+func expandIdents(query string, names map[string]string, allow *Allowlist) (string, error) {
+	var sb strings.Builder
+	used := make(map[string]bool, len(names))
+	for i := 0; i < len(query); i++ {
+		if j := skipQuoted(query, i); j > i {
+			sb.WriteString(query[i:j])
+			i = j - 1
+			continue
+		}
+		if query[i] == '{' && i+1 < len(query) && isNameStart(query[i+1]) {
+			j := i + 1
+			for j < len(query) && isNameChar(query[j]) {
+				j++
+			}
+			if j < len(query) && query[j] == '}' {
+				key := query[i+1 : j]
+				name, ok := names[key]
+				if !ok {
+					return "", fmt.Errorf("mysql: identifier placeholder {%s} has no name", key)
+				}
+				var quoted string
+				var err error
+				if allow != nil {
+					quoted, err = allow.Quote(name)
+				} else {
+					quoted, err = QuoteIdent(name)
+				}
+				if err != nil {
+					return "", err
+				}
+				used[key] = true
+				sb.WriteString(quoted)
+				i = j
+				continue
+			}
+		}
+		sb.WriteByte(query[i])
+	}
+	for key := range names {
+		if !used[key] {
+			return "", fmt.Errorf("mysql: identifier {%s} is not used by the query", key)
+		}
+	}
+	return sb.String(), nil
+}
+
+// Allowlist is a set of identifiers, such as the columns a list API may sort
+// by. Names are matched case-insensitively. A listed name may be qualified,
+// such as "archive.orders", since it comes from the program.
+type Allowlist struct {
+	names map[string]string
+}
+
+func NewAllowlist(names ...string) *Allowlist {
+	a := &Allowlist{names: make(map[string]string, len(names))}
+	for _, name := range names {
+		a.names[strings.ToLower(name)] = name
+	}
+	return a
+}
+
+// Names returns the allowed names, sorted.
+func (a *Allowlist) Names() []string {
+	names := make([]string, 0, len(a.names))
+	for _, name := range a.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Allowed returns the name as listed, or false.
+func (a *Allowlist) Allowed(name string) (string, bool) {
+	listed, ok := a.names[strings.ToLower(name)]
+	return listed, ok
+}
+
+// Quote returns the quoted name, or ErrIdentNotAllowed.
+func (a *Allowlist) Quote(name string) (string, error) {
+	listed, ok := a.Allowed(name)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrIdentNotAllowed, name)
+	}
+	return QuoteQualifiedIdent(listed)
+}
+
+// ExpandIdents is ExpandIdents with every name checked against a.
+func (a *Allowlist) ExpandIdents(query string, names map[string]string) (string, error) {
+	return expandIdents(query, names, a)
+}
+
+func (m *Mysql) schemaAllowlist(query string, args ...interface{}) (*Allowlist, error) {
+	names, err := queryStrings(m, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return NewAllowlist(names...), nil
+}
+
+// TableAllowlist returns the tables of the current database.
+func (m *Mysql) TableAllowlist() (*Allowlist, error) {
+	return m.schemaAllowlist("SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE()")
+}
+
+// ColumnAllowlist returns the columns of table in the current database.
+func (m *Mysql) ColumnAllowlist(table string) (*Allowlist, error) {
+	return m.schemaAllowlist("SELECT COLUMN_NAME FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?", table)
+}
+
+// OrderByIdent adds an ORDER BY on a column name that may come from user
+// input: it is always quoted, never read as an expression.
+func (b *Builder) OrderByIdent(column string, desc bool) *Builder {
+	quoted, err := QuoteIdent(column)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	if desc {
+		quoted += " DESC"
+	}
+	b.orderBy = append(b.orderBy, quoted)
+	return b
+}
@@ -0,0 +1,139 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func TestQuoteIdent(t *testing.T) {
+	tests := []struct {
+		name    string
+		ident   string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain", ident: "users", want: "`users`"},
+		{name: "embedded backtick", ident: "a`b", want: "`a``b`"},
+		{name: "injection", ident: "x`; DROP TABLE t; --", want: "`x``; DROP TABLE t; --`"},
+		{name: "dotted name is one identifier", ident: "mysql.user", want: "`mysql.user`"},
+		{name: "empty", ident: "", wantErr: true},
+		{name: "trailing space", ident: "a ", wantErr: true},
+		{name: "NUL", ident: "a\x00b", wantErr: true},
+		{name: "too long", ident: string(make([]byte, 65)), wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := QuoteIdent(tt.ident)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExpandIdents(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		names   map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "table and column",
+			query: "SELECT * FROM {table} ORDER BY {sort} DESC",
+			names: map[string]string{"table": "orders_3", "sort": "created_at"},
+			want:  "SELECT * FROM `orders_3` ORDER BY `created_at` DESC",
+		},
+		{
+			name:  "embedded backtick",
+			query: "SELECT {col} FROM t",
+			names: map[string]string{"col": "a`b"},
+			want:  "SELECT `a``b` FROM t",
+		},
+		{
+			name:  "dotted name",
+			query: "SELECT * FROM {table}",
+			names: map[string]string{"table": "mysql.user"},
+			want:  "SELECT * FROM `mysql.user`",
+		},
+		{
+			name:  "placeholders in quotes and comments",
+			query: "SELECT '{t}', \"{t}\", `{t}` /* {t} */ FROM {t} # {t}\n-- {t}\n",
+			names: map[string]string{"t": "x"},
+			want:  "SELECT '{t}', \"{t}\", `{t}` /* {t} */ FROM `x` # {t}\n-- {t}\n",
+		},
+		{
+			name:  "braces that are not placeholders",
+			query: "SELECT '{', {1}, { t } FROM {t}",
+			names: map[string]string{"t": "x"},
+			want:  "SELECT '{', {1}, { t } FROM `x`",
+		},
+		{
+			name:    "unused name",
+			query:   "SELECT * FROM {table}",
+			names:   map[string]string{"table": "t", "sort": "id"},
+			wantErr: true,
+		},
+		{
+			name:    "missing name",
+			query:   "SELECT * FROM {table} ORDER BY {sort}",
+			names:   map[string]string{"table": "t"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid name",
+			query:   "SELECT * FROM {table}",
+			names:   map[string]string{"table": ""},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		got, err := ExpandIdents(tt.query, tt.names)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s:\n got %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSkipQuoted(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "not quoted", query: "a = ?", want: 0},
+		{name: "single quotes", query: "'a?b' x", want: 5},
+		{name: "escaped quote", query: `'a\'?' x`, want: 6},
+		{name: "double quotes", query: `"?" x`, want: 3},
+		{name: "backticks do not escape", query: "`a\\` x", want: 4},
+		{name: "doubled backtick", query: "`a``b`", want: 3},
+		{name: "unterminated", query: "'a?", want: 3},
+		{name: "hash comment", query: "# ?\nx", want: 4},
+		{name: "dash comment", query: "-- ?\nx", want: 5},
+		{name: "dash without space", query: "--?", want: 0},
+		{name: "block comment", query: "/* ? */x", want: 7},
+		{name: "unterminated block comment", query: "/* ?", want: 4},
+	}
+	for _, tt := range tests {
+		if got := skipQuoted(tt.query, 0); got != tt.want {
+			t.Errorf("%s: skipQuoted(%q) = %d, want %d", tt.name, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestOrderByIdent(t *testing.T) {
+	query, _, err := Select().From("t").OrderByIdent("name DESC; x", true).ToSQL()
+	if err != nil || query != "SELECT * FROM `t` ORDER BY `name DESC; x` DESC" {
+		t.Fatalf("got %q, %v", query, err)
+	}
+	_, _, err = Select().From("t").OrderByIdent("", false).OrderByIdent("a ", false).ToSQL()
+	if err == nil || err.Error() != `mysql: invalid identifier ""` {
+		t.Fatalf("got %v, want the first error", err)
+	}
+}
@@ -0,0 +1,72 @@
+package mysql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// isSliceArg reports whether arg is a slice to expand into a list of
+// placeholders. []byte and driver.Valuer are passed to the driver as is.
+func isSliceArg(arg interface{}) bool {
+	if arg == nil {
+		return false
+	}
+	if _, ok := arg.(driver.Valuer); ok {
+		return false
+	}
+	t := reflect.TypeOf(arg)
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+}
+
+// expandSlices rewrites the ? of each slice argument to one placeholder per
+// element, so that
+//
+//	m.QueryForMapSlice("SELECT * FROM users WHERE id IN (?)", []int64{1, 2, 3})
+//
+// runs WHERE id IN (?, ?, ?). An empty slice is an error: IN () is not valid
+// SQL, and no list fits both IN and NOT IN.
+func expandSlices(query string, args []interface{}) (string, []interface{}, error) {
+	found := false
+	for _, arg := range args {
+		if isSliceArg(arg) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return query, args, nil
+	}
+
+	var sb strings.Builder
+	expanded := make([]interface{}, 0, len(args))
+	n := 0
+	for i := 0; i < len(query); i++ {
+		if j := skipQuoted(query, i); j > i {
+			sb.WriteString(query[i:j])
+			i = j - 1
+			continue
+		}
+		if query[i] != '?' || n >= len(args) {
+			sb.WriteByte(query[i])
+			continue
+		}
+		arg := args[n]
+		n++
+		if !isSliceArg(arg) {
+			sb.WriteByte('?')
+			expanded = append(expanded, arg)
+			continue
+		}
+		v := reflect.ValueOf(arg)
+		if v.Len() == 0 {
+			return query, args, fmt.Errorf("mysql: argument %d is an empty slice", n)
+		}
+		sb.WriteString(placeholders(v.Len()))
+		for k := 0; k < v.Len(); k++ {
+			expanded = append(expanded, v.Index(k).Interface())
+		}
+	}
+	return sb.String(), append(expanded, args[n:]...), nil
+}
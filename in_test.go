@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestExpandSlices(t *testing.T) {
+	valuer := sql.NullString{String: "v", Valid: true}
+	tests := []struct {
+		name    string
+		query   string
+		args    []interface{}
+		want    string
+		wargs   []interface{}
+		wantErr bool
+	}{
+		{
+			name:  "no slices",
+			query: "SELECT * FROM t WHERE a = ?",
+			args:  []interface{}{1},
+			want:  "SELECT * FROM t WHERE a = ?",
+			wargs: []interface{}{1},
+		},
+		{
+			name:  "slice between scalars",
+			query: "SELECT * FROM t WHERE a = ? AND id IN (?) AND d = ?",
+			args:  []interface{}{1, []int64{2, 3}, 4},
+			want:  "SELECT * FROM t WHERE a = ? AND id IN (?, ?) AND d = ?",
+			wargs: []interface{}{1, int64(2), int64(3), 4},
+		},
+		{
+			name:    "empty slice",
+			query:   "SELECT * FROM t WHERE a = ? AND id NOT IN (?)",
+			args:    []interface{}{1, []int{}},
+			wantErr: true,
+		},
+		{
+			name:  "bytes and valuers are not expanded",
+			query: "SELECT * FROM t WHERE a = ? AND b = ? AND c IN (?)",
+			args:  []interface{}{[]byte("x"), valuer, []string{"p", "q"}},
+			want:  "SELECT * FROM t WHERE a = ? AND b = ? AND c IN (?, ?)",
+			wargs: []interface{}{[]byte("x"), valuer, "p", "q"},
+		},
+		{
+			name:  "question marks in quotes and comments",
+			query: "SELECT '?', \"?\", `?` /* ? */ FROM t WHERE id IN (?) -- ?\n",
+			args:  []interface{}{[]int{1, 2}},
+			want:  "SELECT '?', \"?\", `?` /* ? */ FROM t WHERE id IN (?, ?) -- ?\n",
+			wargs: []interface{}{1, 2},
+		},
+		{
+			name:  "array of interfaces",
+			query: "SELECT * FROM t WHERE (a, b) IN ((?))",
+			args:  []interface{}{[]interface{}{"x", 1}},
+			want:  "SELECT * FROM t WHERE (a, b) IN ((?, ?))",
+			wargs: []interface{}{"x", 1},
+		},
+		{
+			name:  "more args than placeholders",
+			query: "SELECT ?",
+			args:  []interface{}{[]int{1}, 2},
+			want:  "SELECT ?",
+			wargs: []interface{}{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		query, args, err := expandSlices(tt.query, tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if query != tt.want {
+			t.Errorf("%s:\n got %q\nwant %q", tt.name, query, tt.want)
+		}
+		if !reflect.DeepEqual(args, tt.wargs) {
+			t.Errorf("%s: args %#v, want %#v", tt.name, args, tt.wargs)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// insertFields returns the fields an insert writes, and the auto-increment
+// field when it is zero and left to the database.
+func (mi *modelInfo) insertFields(v reflect.Value) ([]*fieldInfo, *fieldInfo) {
+	var fields []*fieldInfo
+	var auto *fieldInfo
+	for _, f := range mi.Fields {
+		fv := v.FieldByIndex(f.Index)
+		if f == mi.auto && fv.IsZero() {
+			auto = f
+			continue
+		}
+		if (f.has("omitempty") || f == mi.softDelete) && fv.IsZero() {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields, auto
+}
+
+// fieldColumns returns the quoted columns of fields and their values in v.
+func fieldColumns(v reflect.Value, fields []*fieldInfo) ([]string, []interface{}) {
+	cols := make([]string, len(fields))
+	args := make([]interface{}, len(fields))
+	for i, f := range fields {
+		cols[i] = quoteIdent(f.Column)
+		args[i] = v.FieldByIndex(f.Index).Interface()
+	}
+	return cols, args
+}
+
+func insertModel(ctx context.Context, exec Executor, model interface{}) (int64, error) {
+	v, err := modelValue(model)
+	if err != nil {
+		return -1, err
+	}
+	mi := getModelInfo(v.Type(), exec.mysql().NamingStrategy())
+	if err := beforeInsert(exec, v); err != nil {
+		return -1, err
+	}
+	if err := mi.fillCreateTimes(v, exec.mysql().now()); err != nil {
+		return -1, err
+	}
+	if err := validate(exec, v); err != nil {
+		return -1, err
+	}
+	fields, auto := mi.insertFields(v)
+	cols, args := fieldColumns(v, fields)
+
+	query := "INSERT INTO " + quoteIdent(mi.tableName(v)) + " (" + strings.Join(cols, ", ") + ") VALUES (" + placeholders(len(cols)) + ")"
+	res, err := exec.ExecContext(ctx, query, args...)
+	if err != nil {
+		return -1, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+	if auto != nil {
+		if err := setFieldValue(v.FieldByIndex(auto.Index), id, exec.mysql().Location()); err != nil {
+			return id, err
+		}
+	}
+	mi.takeSnapshot(v)
+	if err := afterInsert(exec, v); err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
+// InsertModel inserts model, a pointer to struct, into its table and returns
+// LastInsertId. A zero autoIncrement field is left to the database and then
+// set from LastInsertId.
+func (m *Mysql) InsertModel(model interface{}) (int64, error) {
+	return insertModel(context.Background(), m, model)
+}
+
+func (tx *Tx) InsertModel(model interface{}) (int64, error) {
+	return insertModel(context.Background(), tx, model)
+}
@@ -0,0 +1,86 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrStaleObject matches every *StaleObjectError with errors.Is.
+var ErrStaleObject = errors.New("mysql: stale object")
+
+// StaleObjectError is returned when an update of a versioned model matched
+// no row, because the row was changed or deleted since it was loaded.
+type StaleObjectError struct {
+	Table   string
+	Version interface{}
+}
+
+func (e *StaleObjectError) Error() string {
+	return fmt.Sprintf("mysql: stale object: %s version %v was changed by another update", e.Table, e.Version)
+}
+
+func (e *StaleObjectError) Is(target error) bool {
+	return target == ErrStaleObject
+}
+
+// reloadModel loads the current row of model by its primary key. The other
+// fields are cleared first, since NULL columns are not scanned; on failure
+// model is left as it was.
+func reloadModel(ctx context.Context, exec Executor, model interface{}) error {
+	v, err := modelValue(model)
+	if err != nil {
+		return err
+	}
+	mi := getModelInfo(v.Type(), exec.mysql().NamingStrategy())
+	where, args, err := mi.pkWhere(v)
+	if err != nil {
+		return err
+	}
+	old := reflect.New(v.Type()).Elem()
+	old.Set(v)
+	v.Set(reflect.Zero(v.Type()))
+	for _, f := range mi.pks {
+		v.FieldByIndex(f.Index).Set(old.FieldByIndex(f.Index))
+	}
+	ok, err := findModel(ctx, exec, model, []ModelOption{Where(where, args...)})
+	if err == nil && !ok {
+		err = ErrNotFound
+	}
+	if err != nil {
+		v.Set(old)
+		return err
+	}
+	return nil
+}
+
+func updateModelRetry(ctx context.Context, exec Executor, model interface{}, attempts int, merge func() error) (int64, error) {
+	for i := 0; ; i++ {
+		if err := merge(); err != nil {
+			return -1, err
+		}
+		n, err := updateModel(ctx, exec, model, UpdateOptions{})
+		if !errors.Is(err, ErrStaleObject) || i+1 >= attempts {
+			return n, err
+		}
+		if err := reloadModel(ctx, exec, model); err != nil {
+			return -1, err
+		}
+	}
+}
+
+// UpdateModelRetry applies merge to model and updates it. When the update
+// finds a stale version, it reloads model from the database and applies
+// merge again, making at most attempts updates.
+//
+//	_, err := m.UpdateModelRetry(account, 3, func() error {
+//		account.Balance += amount
+//		return nil
+//	})
+//
+// There is no Tx version: a reload inside a REPEATABLE READ transaction
+// would see the same stale row again.
+func (m *Mysql) UpdateModelRetry(model interface{}, attempts int, merge func() error) (int64, error) {
+	return updateModelRetry(context.Background(), m, model, attempts, merge)
+}
@@ -0,0 +1,817 @@
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mapper holds named SQL statements loaded from MyBatis style XML files:
+//
+//	<mapper namespace="order">
+//		<sql id="columns">id, user_id, status, created_at</sql>
+//		<select id="findByUser">
+//			SELECT <include refid="columns"/> FROM orders
+//			<where>
+//				<if test="UserID != null">AND user_id = #{UserID}</if>
+//				<if test="Status">AND status IN
+//					<foreach collection="Status" item="s" open="(" separator="," close=")">#{s}</foreach>
+//				</if>
+//			</where>
+//		</select>
+//	</mapper>
+//
+// select, insert, update and delete elements are statements, named
+// namespace.id; sql elements are fragments for include, whose refid may
+// name another namespace. Statements can use <if test="...">, <where>,
+// which drops a leading AND or OR, <set>, which drops a trailing comma,
+// <foreach collection item index open separator close> and <include>.
+// #{path} becomes a ? bound to a field or key of the parameters, or of a
+// foreach item; a slice is expanded as in the query methods. See testExpr
+// for the test language.
+//
+// Every file is parsed and every include resolved when it is loaded, so
+// mistakes fail at startup. In development mode the files are checked for
+// changes before a statement is rendered, at most once a second, and
+// reloaded when they change.
+type Mapper struct {
+	mu          sync.RWMutex
+	fsys        fs.FS
+	patterns    []string
+	development bool
+	checked     time.Time
+	statements  map[string]*mappedStatement
+	files       map[string]time.Time
+}
+
+// mapperCheckInterval is how often development mode looks for changed
+// files.
+const mapperCheckInterval = time.Second
+
+type mappedStatement struct {
+	Name  string
+	Kind  string
+	File  string
+	nodes []sqlNode
+}
+
+// LoadMapper loads the mapper files matching the glob patterns.
+func LoadMapper(patterns ...string) (*Mapper, error) {
+	mp := &Mapper{patterns: patterns}
+	if err := mp.Reload(); err != nil {
+		return nil, err
+	}
+	return mp, nil
+}
+
+// LoadMapperFS loads mapper files from fsys, such as an embed.FS.
+func LoadMapperFS(fsys fs.FS, patterns ...string) (*Mapper, error) {
+	mp := &Mapper{fsys: fsys, patterns: patterns}
+	if err := mp.Reload(); err != nil {
+		return nil, err
+	}
+	return mp, nil
+}
+
+// SetDevelopment turns on reloading of changed files.
+func (mp *Mapper) SetDevelopment(development bool) {
+	mp.mu.Lock()
+	mp.development = development
+	mp.mu.Unlock()
+}
+
+func (mp *Mapper) glob(pattern string) ([]string, error) {
+	if mp.fsys != nil {
+		return fs.Glob(mp.fsys, pattern)
+	}
+	return filepath.Glob(pattern)
+}
+
+func (mp *Mapper) stat(name string) (fs.FileInfo, error) {
+	if mp.fsys != nil {
+		return fs.Stat(mp.fsys, name)
+	}
+	return os.Stat(name)
+}
+
+func (mp *Mapper) readFile(name string) ([]byte, error) {
+	if mp.fsys != nil {
+		return fs.ReadFile(mp.fsys, name)
+	}
+	return os.ReadFile(name)
+}
+
+// scan returns the files matching the patterns with their modification
+// times.
+func (mp *Mapper) scan() (map[string]time.Time, error) {
+	files := make(map[string]time.Time)
+	for _, pattern := range mp.patterns {
+		names, err := mp.glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("mysql: no mapper files match %q", pattern)
+		}
+		for _, name := range names {
+			fi, err := mp.stat(name)
+			if err != nil {
+				return nil, err
+			}
+			files[name] = fi.ModTime()
+		}
+	}
+	return files, nil
+}
+
+// Reload parses the files again. On error the loaded statements are kept.
+func (mp *Mapper) Reload() error {
+	files, err := mp.scan()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	statements := make(map[string]*mappedStatement)
+	fragments := make(map[string]*mappedStatement)
+	for _, name := range names {
+		data, err := mp.readFile(name)
+		if err != nil {
+			return err
+		}
+		parsed, err := parseMapperFile(name, data)
+		if err != nil {
+			return err
+		}
+		for _, s := range parsed {
+			target := statements
+			if s.Kind == "sql" {
+				target = fragments
+			}
+			if prev, ok := target[s.Name]; ok {
+				return fmt.Errorf("mysql: %s: %s %s is already defined in %s", name, s.Kind, s.Name, prev.File)
+			}
+			target[s.Name] = s
+		}
+	}
+	for _, group := range []map[string]*mappedStatement{fragments, statements} {
+		for _, s := range group {
+			if err := resolveIncludes(s, fragments, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	mp.mu.Lock()
+	mp.statements = statements
+	mp.files = files
+	mp.mu.Unlock()
+	return nil
+}
+
+// changed reports whether a file was added, removed or modified.
+func (mp *Mapper) changed() bool {
+	files, err := mp.scan()
+	if err != nil {
+		return true
+	}
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	if len(files) != len(mp.files) {
+		return true
+	}
+	for name, t := range files {
+		if old, ok := mp.files[name]; !ok || !old.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDue reports whether development mode is on and the files have not
+// been checked for mapperCheckInterval.
+func (mp *Mapper) checkDue() bool {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	if !mp.development {
+		return false
+	}
+	now := time.Now()
+	if now.Sub(mp.checked) < mapperCheckInterval {
+		return false
+	}
+	mp.checked = now
+	return true
+}
+
+func (mp *Mapper) statement(name string) (*mappedStatement, error) {
+	if mp.checkDue() && mp.changed() {
+		if err := mp.Reload(); err != nil {
+			return nil, err
+		}
+	}
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	s, ok := mp.statements[name]
+	if !ok {
+		return nil, fmt.Errorf("mysql: no mapper statement %q", name)
+	}
+	return s, nil
+}
+
+// Statements returns the names of the loaded statements.
+func (mp *Mapper) Statements() []string {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	names := make([]string, 0, len(mp.statements))
+	for name := range mp.statements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (mp *Mapper) render(name string, params interface{}, naming NamingStrategy) (string, []interface{}, error) {
+	s, err := mp.statement(name)
+	if err != nil {
+		return "", nil, err
+	}
+	c := &renderContext{params: params, naming: naming}
+	var sb strings.Builder
+	if err := renderNodes(c, &sb, s.nodes); err != nil {
+		return "", nil, fmt.Errorf("mysql: statement %s: %v", name, err)
+	}
+	return compactSQL(sb.String()), c.args, nil
+}
+
+// Render returns the query and args of statement name for params.
+func (mp *Mapper) Render(name string, params interface{}) (string, []interface{}, error) {
+	return mp.render(name, params, DefaultNaming)
+}
+
+func parseMapperFile(file string, data []byte) ([]*mappedStatement, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var namespace string
+	var statements []*mappedStatement
+	inMapper := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("mysql: %s: %v", file, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if !inMapper {
+				if t.Name.Local != "mapper" {
+					return nil, fmt.Errorf("mysql: %s: root element is <%s>, want <mapper>", file, t.Name.Local)
+				}
+				namespace = xmlAttr(t, "namespace")
+				inMapper = true
+				continue
+			}
+			switch t.Name.Local {
+			case "select", "insert", "update", "delete", "sql":
+			default:
+				return nil, fmt.Errorf("mysql: %s: unknown element <%s>", file, t.Name.Local)
+			}
+			id := xmlAttr(t, "id")
+			if id == "" {
+				return nil, fmt.Errorf("mysql: %s: <%s> has no id", file, t.Name.Local)
+			}
+			nodes, err := parseNodes(dec, namespace)
+			if err != nil {
+				return nil, fmt.Errorf("mysql: %s: %s %s: %v", file, t.Name.Local, id, err)
+			}
+			statements = append(statements, &mappedStatement{
+				Name:  qualifiedName(namespace, id),
+				Kind:  t.Name.Local,
+				File:  file,
+				nodes: nodes,
+			})
+		case xml.EndElement:
+			inMapper = false
+		}
+	}
+	return statements, nil
+}
+
+func xmlAttr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func qualifiedName(namespace string, id string) string {
+	if namespace == "" || strings.Contains(id, ".") {
+		return id
+	}
+	return namespace + "." + id
+}
+
+// sqlNode is a piece of a mapped statement.
+type sqlNode interface {
+	render(c *renderContext, sb *strings.Builder) error
+}
+
+type textPart struct {
+	text  string
+	param string
+}
+
+type textNode []textPart
+
+type ifNode struct {
+	test     testExpr
+	children []sqlNode
+}
+
+type whereNode []sqlNode
+
+type setNode []sqlNode
+
+type foreachNode struct {
+	collection string
+	item       string
+	index      string
+	open       string
+	separator  string
+	close      string
+	children   []sqlNode
+}
+
+type includeNode struct {
+	refid  string
+	target *mappedStatement
+}
+
+// parseNodes reads the content of the current element up to its end.
+func parseNodes(dec *xml.Decoder, namespace string) ([]sqlNode, error) {
+	var nodes []sqlNode
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			node, err := parseText(string(t))
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		case xml.EndElement:
+			return nodes, nil
+		case xml.StartElement:
+			children, err := parseNodes(dec, namespace)
+			if err != nil {
+				return nil, err
+			}
+			switch t.Name.Local {
+			case "if":
+				test, err := parseTestExpr(xmlAttr(t, "test"))
+				if err != nil {
+					return nil, err
+				}
+				nodes = append(nodes, &ifNode{test: test, children: children})
+			case "where":
+				nodes = append(nodes, whereNode(children))
+			case "set":
+				nodes = append(nodes, setNode(children))
+			case "foreach":
+				f := &foreachNode{
+					collection: xmlAttr(t, "collection"),
+					item:       xmlAttr(t, "item"),
+					index:      xmlAttr(t, "index"),
+					open:       xmlAttr(t, "open"),
+					separator:  xmlAttr(t, "separator"),
+					close:      xmlAttr(t, "close"),
+					children:   children,
+				}
+				if f.collection == "" {
+					return nil, fmt.Errorf("<foreach> has no collection")
+				}
+				nodes = append(nodes, f)
+			case "include":
+				refid := xmlAttr(t, "refid")
+				if refid == "" {
+					return nil, fmt.Errorf("<include> has no refid")
+				}
+				nodes = append(nodes, &includeNode{refid: qualifiedName(namespace, refid)})
+			default:
+				return nil, fmt.Errorf("unknown element <%s>", t.Name.Local)
+			}
+		}
+	}
+}
+
+func parseText(s string) (textNode, error) {
+	var parts textNode
+	for {
+		i := strings.Index(s, "#{")
+		if i < 0 {
+			break
+		}
+		j := strings.IndexByte(s[i:], '}')
+		if j < 0 {
+			return nil, fmt.Errorf("unterminated #{ in %q", s)
+		}
+		param, _, _ := strings.Cut(s[i+2:i+j], ",")
+		param = strings.TrimSpace(param)
+		if param == "" {
+			return nil, fmt.Errorf("empty #{} in %q", s)
+		}
+		parts = append(parts, textPart{text: s[:i], param: param})
+		s = s[i+j+1:]
+	}
+	if s != "" {
+		parts = append(parts, textPart{text: s})
+	}
+	return parts, nil
+}
+
+func childNodes(n sqlNode) []sqlNode {
+	switch n := n.(type) {
+	case *ifNode:
+		return n.children
+	case whereNode:
+		return n
+	case setNode:
+		return n
+	case *foreachNode:
+		return n.children
+	}
+	return nil
+}
+
+// resolveIncludes points the includes of s at their fragments, failing on
+// a missing fragment or an include cycle.
+func resolveIncludes(s *mappedStatement, fragments map[string]*mappedStatement, path []string) error {
+	for _, name := range path {
+		if name == s.Name {
+			return fmt.Errorf("mysql: %s: include cycle %s", s.File, strings.Join(append(path, s.Name), " -> "))
+		}
+	}
+	path = append(path, s.Name)
+	var walk func(nodes []sqlNode) error
+	walk = func(nodes []sqlNode) error {
+		for _, n := range nodes {
+			if inc, ok := n.(*includeNode); ok {
+				target, ok := fragments[inc.refid]
+				if !ok {
+					return fmt.Errorf("mysql: %s: %s includes unknown fragment %s", s.File, s.Name, inc.refid)
+				}
+				inc.target = target
+				if err := resolveIncludes(target, fragments, path); err != nil {
+					return err
+				}
+			}
+			if err := walk(childNodes(n)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(s.nodes)
+}
+
+type renderContext struct {
+	params interface{}
+	naming NamingStrategy
+	scopes []map[string]interface{}
+	args   []interface{}
+}
+
+// value resolves a dotted path against the foreach variables, then the
+// parameters. Map keys are matched exactly, struct fields by column or field
+// name.
+func (c *renderContext) value(path string) (interface{}, bool) {
+	segments := strings.Split(path, ".")
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if v, ok := c.scopes[i][segments[0]]; ok {
+			return c.resolve(v, segments[1:])
+		}
+	}
+	return c.resolve(c.params, segments)
+}
+
+func (c *renderContext) resolve(v interface{}, segments []string) (interface{}, bool) {
+	for _, seg := range segments {
+		rv := reflect.ValueOf(indirectValue(v))
+		if !rv.IsValid() {
+			return nil, true
+		}
+		switch rv.Kind() {
+		case reflect.Map:
+			if rv.Type().Key().Kind() != reflect.String {
+				return nil, false
+			}
+			e := rv.MapIndex(reflect.ValueOf(seg).Convert(rv.Type().Key()))
+			if !e.IsValid() {
+				return nil, false
+			}
+			v = e.Interface()
+		case reflect.Struct:
+			if f := getModelInfo(rv.Type(), c.naming).lookup(seg); f != nil {
+				v = rv.FieldByIndex(f.Index).Interface()
+			} else if sf, ok := rv.Type().FieldByName(seg); ok && sf.IsExported() {
+				v = rv.FieldByIndex(sf.Index).Interface()
+			} else {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func renderNodes(c *renderContext, sb *strings.Builder, nodes []sqlNode) error {
+	for _, n := range nodes {
+		if err := n.render(c, sb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n textNode) render(c *renderContext, sb *strings.Builder) error {
+	for _, p := range n {
+		sb.WriteString(p.text)
+		if p.param == "" {
+			continue
+		}
+		v, ok := c.value(p.param)
+		if !ok {
+			return fmt.Errorf("parameter %s has no value", p.param)
+		}
+		sb.WriteByte('?')
+		c.args = append(c.args, v)
+	}
+	return nil
+}
+
+func (n *ifNode) render(c *renderContext, sb *strings.Builder) error {
+	v, err := n.test(c)
+	if err != nil || !truthy(v) {
+		return err
+	}
+	return renderNodes(c, sb, n.children)
+}
+
+// trimKeyword drops a leading keyword, such as AND, from s.
+func trimKeyword(s string, keywords ...string) string {
+	for _, k := range keywords {
+		if len(s) > len(k) && strings.EqualFold(s[:len(k)], k) {
+			switch s[len(k)] {
+			case ' ', '\t', '\n', '\r', '(':
+				return strings.TrimSpace(s[len(k):])
+			}
+		}
+	}
+	return s
+}
+
+func (n whereNode) render(c *renderContext, sb *strings.Builder) error {
+	var inner strings.Builder
+	if err := renderNodes(c, &inner, n); err != nil {
+		return err
+	}
+	if s := trimKeyword(strings.TrimSpace(inner.String()), "AND", "OR"); s != "" {
+		sb.WriteString(" WHERE " + s + " ")
+	}
+	return nil
+}
+
+func (n setNode) render(c *renderContext, sb *strings.Builder) error {
+	var inner strings.Builder
+	if err := renderNodes(c, &inner, n); err != nil {
+		return err
+	}
+	if s := strings.TrimSuffix(strings.TrimSpace(inner.String()), ","); s != "" {
+		sb.WriteString(" SET " + s + " ")
+	}
+	return nil
+}
+
+func (n *foreachNode) render(c *renderContext, sb *strings.Builder) error {
+	v, ok := c.value(n.collection)
+	if !ok {
+		return fmt.Errorf("foreach collection %s has no value", n.collection)
+	}
+	rv := reflect.ValueOf(indirectValue(v))
+	if !rv.IsValid() {
+		return nil
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Errorf("foreach collection %s is a %s, not a slice", n.collection, rv.Type())
+	}
+	if rv.Len() == 0 {
+		return nil
+	}
+	sb.WriteString(n.open)
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			sb.WriteString(n.separator)
+		}
+		scope := make(map[string]interface{}, 2)
+		if n.item != "" {
+			scope[n.item] = rv.Index(i).Interface()
+		}
+		if n.index != "" {
+			scope[n.index] = i
+		}
+		c.scopes = append(c.scopes, scope)
+		var inner strings.Builder
+		err := renderNodes(c, &inner, n.children)
+		c.scopes = c.scopes[:len(c.scopes)-1]
+		if err != nil {
+			return err
+		}
+		sb.WriteString(strings.TrimSpace(inner.String()))
+	}
+	sb.WriteString(n.close)
+	return nil
+}
+
+func (n *includeNode) render(c *renderContext, sb *strings.Builder) error {
+	return renderNodes(c, sb, n.target.nodes)
+}
+
+// compactSQL collapses runs of white space outside quotes.
+func compactSQL(query string) string {
+	var sb strings.Builder
+	space := false
+	for i := 0; i < len(query); i++ {
+		if j := skipQuoted(query, i); j > i {
+			if space && sb.Len() > 0 {
+				sb.WriteByte(' ')
+			}
+			space = false
+			sb.WriteString(query[i:j])
+			i = j - 1
+			continue
+		}
+		switch query[i] {
+		case ' ', '\t', '\n', '\r':
+			space = true
+		default:
+			if space && sb.Len() > 0 {
+				sb.WriteByte(' ')
+			}
+			space = false
+			sb.WriteByte(query[i])
+		}
+	}
+	return sb.String()
+}
+
+// Statement is a mapped statement rendered for its parameters, ready to run
+// on the Mysql or Tx it came from.
+type Statement struct {
+	exec  Executor
+	query string
+	args  []interface{}
+	err   error
+}
+
+func newStatement(exec Executor, name string, params interface{}) *Statement {
+	m := exec.mysql()
+	if m == nil || m.mapper == nil {
+		return &Statement{exec: exec, err: fmt.Errorf("mysql: no mapper set, see SetMapper")}
+	}
+	query, args, err := m.mapper.render(name, params, m.NamingStrategy())
+	return &Statement{exec: exec, query: query, args: args, err: err}
+}
+
+// SQL returns the rendered query and args.
+func (s *Statement) SQL() (string, []interface{}, error) {
+	return s.query, s.args, s.err
+}
+
+func (s *Statement) Exec() (sql.Result, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.exec.ExecContext(context.Background(), s.query, s.args...)
+}
+
+// Insert runs the statement and returns the last insert id.
+func (s *Statement) Insert() (int64, error) {
+	res, err := s.Exec()
+	if err != nil {
+		return -1, err
+	}
+	return res.LastInsertId()
+}
+
+// Update runs the statement and returns the number of rows affected.
+func (s *Statement) Update() (int64, error) {
+	res, err := s.Exec()
+	if err != nil {
+		return -1, err
+	}
+	return res.RowsAffected()
+}
+
+func (s *Statement) QueryForMap() (map[string]interface{}, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	row, _, err := QueryOne[map[string]interface{}](context.Background(), s.exec, s.query, s.args...)
+	return row, err
+}
+
+func (s *Statement) QueryForMapSlice() ([]map[string]interface{}, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return QueryAll[map[string]interface{}](context.Background(), s.exec, s.query, s.args...)
+}
+
+func (s *Statement) QueryForModel(model interface{}) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	v, err := modelValue(model)
+	if err != nil {
+		return false, err
+	}
+	rows, err := s.exec.QueryContext(context.Background(), s.query, s.args...)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	m := s.exec.mysql()
+	scanner, err := newModelScanner(rows, getModelInfo(v.Type(), m.NamingStrategy()), s.exec)
+	if err != nil {
+		return false, m.convertError(err)
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return false, m.convertError(err)
+		}
+		return false, m.notFound()
+	}
+	if err := scanner.scan(rows, v); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *Statement) QueryForModelSlice(models interface{}) error {
+	if s.err != nil {
+		return s.err
+	}
+	sliceValue, elemType, err := modelSlice(models)
+	if err != nil {
+		return err
+	}
+	rows, err := s.exec.QueryContext(context.Background(), s.query, s.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	m := s.exec.mysql()
+	return appendModels(rows, sliceValue, getModelInfo(elemType, m.NamingStrategy()), s.exec)
+}
+
+// SetMapper sets the statements run by Named.
+func (m *Mysql) SetMapper(mapper *Mapper) {
+	m.mapper = mapper
+}
+
+// Named renders the mapper statement name, such as "order.findByUser", for
+// params, a map or struct:
+//
+//	err := m.Named("order.findByUser", map[string]interface{}{"UserID": 7}).
+//		QueryForModelSlice(&orders)
+func (m *Mysql) Named(name string, params interface{}) *Statement {
+	return newStatement(m, name, params)
+}
+
+func (tx *Tx) Named(name string, params interface{}) *Statement {
+	return newStatement(tx, name, params)
+}
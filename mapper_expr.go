@@ -0,0 +1,339 @@
+package mysql
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// testExpr is a compiled `test` attribute of a mapper <if>. The language is
+// small: parameter paths like user.name, literals ('text', 10, true, null),
+// the comparisons == != < <= > >= (or lt, lte, gt, gte to avoid escaping in
+// XML), and, or, not and parentheses. A bare value is true when it is not
+// nil, not zero and not an empty slice, map or string.
+type testExpr func(c *renderContext) (interface{}, error)
+
+type exprParser struct {
+	src    string
+	tokens []string
+	pos    int
+}
+
+func parseTestExpr(src string) (testExpr, error) {
+	tokens, err := tokenizeExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{src: src, tokens: tokens}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in test %q", p.tokens[p.pos], src)
+	}
+	return e, nil
+}
+
+func tokenizeExpr(src string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string in test %q", src)
+			}
+			tokens = append(tokens, src[i:j+1])
+			i = j + 1
+		case isNameStart(c):
+			j := i + 1
+			for j < len(src) && (isNameChar(src[j]) || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, src[i:j])
+			i = j
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			j := i + 1
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, src[i:j])
+			i = j
+		default:
+			op := ""
+			for _, o := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")"} {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q in test %q", c, src)
+			}
+			tokens = append(tokens, op)
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return strings.ToLower(p.tokens[p.pos])
+	}
+	return ""
+}
+
+func (p *exprParser) or() (testExpr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t == "or" || t == "||"; t = p.peek() {
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(c *renderContext) (interface{}, error) {
+			v, err := l(c)
+			if err != nil || truthy(v) {
+				return true, err
+			}
+			v, err = right(c)
+			return truthy(v), err
+		}
+	}
+	return left, nil
+}
+
+func (p *exprParser) and() (testExpr, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t == "and" || t == "&&"; t = p.peek() {
+		p.pos++
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(c *renderContext) (interface{}, error) {
+			v, err := l(c)
+			if err != nil || !truthy(v) {
+				return false, err
+			}
+			v, err = right(c)
+			return truthy(v), err
+		}
+	}
+	return left, nil
+}
+
+func (p *exprParser) not() (testExpr, error) {
+	if t := p.peek(); t == "not" || t == "!" {
+		p.pos++
+		e, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return func(c *renderContext) (interface{}, error) {
+			v, err := e(c)
+			return !truthy(v), err
+		}, nil
+	}
+	return p.comparison()
+}
+
+var comparisonOps = map[string]string{
+	"==": "==", "!=": "!=", "<": "<", "<=": "<=", ">": ">", ">=": ">=",
+	"lt": "<", "lte": "<=", "gt": ">", "gte": ">=",
+}
+
+func (p *exprParser) comparison() (testExpr, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := comparisonOps[p.peek()]
+	if !ok {
+		return left, nil
+	}
+	p.pos++
+	right, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	return func(c *renderContext) (interface{}, error) {
+		a, err := left(c)
+		if err != nil {
+			return nil, err
+		}
+		b, err := right(c)
+		if err != nil {
+			return nil, err
+		}
+		return compareValues(a, b, op)
+	}, nil
+}
+
+func (p *exprParser) operand() (testExpr, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of test %q", p.src)
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	constant := func(v interface{}) (testExpr, error) {
+		return func(*renderContext) (interface{}, error) { return v, nil }, nil
+	}
+	switch {
+	case t == "(":
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing ) in test %q", p.src)
+		}
+		p.pos++
+		return e, nil
+	case t[0] == '\'' || t[0] == '"':
+		s := t[1 : len(t)-1]
+		s = strings.ReplaceAll(s, `\`+t[:1], t[:1])
+		return constant(s)
+	case t[0] == '-' || t[0] >= '0' && t[0] <= '9':
+		f, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number %q in test %q", t, p.src)
+		}
+		return constant(f)
+	case isNameStart(t[0]):
+		switch strings.ToLower(t) {
+		case "null", "nil":
+			return constant(nil)
+		case "true":
+			return constant(true)
+		case "false":
+			return constant(false)
+		case "and", "or", "not", "lt", "lte", "gt", "gte":
+			return nil, fmt.Errorf("unexpected %q in test %q", t, p.src)
+		}
+		return func(c *renderContext) (interface{}, error) {
+			v, _ := c.value(t)
+			return v, nil
+		}, nil
+	}
+	return nil, fmt.Errorf("unexpected %q in test %q", t, p.src)
+}
+
+// indirectValue follows pointers and interfaces, returning nil for nil.
+func indirectValue(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	for rv.IsValid() && (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
+
+func truthy(v interface{}) bool {
+	v = indirectValue(v)
+	if v == nil {
+		return false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return rv.Len() > 0
+	}
+	return !rv.IsZero()
+}
+
+// comparableValue returns v as a float64, string or bool.
+func comparableValue(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	switch {
+	case isIntegerKind(rv.Kind()) && rv.Kind() >= reflect.Uint:
+		return float64(rv.Uint())
+	case isIntegerKind(rv.Kind()):
+		return float64(rv.Int())
+	case rv.Kind() == reflect.Float32 || rv.Kind() == reflect.Float64:
+		return rv.Float()
+	case rv.Kind() == reflect.String:
+		return rv.String()
+	case rv.Kind() == reflect.Bool:
+		return rv.Bool()
+	}
+	return v
+}
+
+func compareValues(a interface{}, b interface{}, op string) (bool, error) {
+	a, b = indirectValue(a), indirectValue(b)
+	if a == nil || b == nil {
+		switch op {
+		case "==":
+			return a == nil && b == nil, nil
+		case "!=":
+			return a != nil || b != nil, nil
+		}
+		return false, nil
+	}
+	a, b = comparableValue(a), comparableValue(b)
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			return compareOrdered(x, y, op), nil
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return compareOrdered(x, y, op), nil
+		}
+	case bool:
+		if y, ok := b.(bool); ok && (op == "==" || op == "!=") {
+			return (x == y) == (op == "=="), nil
+		}
+	}
+	switch op {
+	case "==":
+		return false, nil
+	case "!=":
+		return true, nil
+	}
+	return false, fmt.Errorf("cannot compare %T %s %T", a, op, b)
+}
+
+func compareOrdered[T float64 | string](a T, b T, op string) bool {
+	switch op {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	}
+	return a >= b
+}
@@ -0,0 +1,297 @@
+package mysql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTestExpr(t *testing.T) {
+	id := int64(7)
+	var nilID *int64
+	params := map[string]interface{}{
+		"id":     &id,
+		"nilID":  nilID,
+		"name":   "bob",
+		"empty":  "",
+		"zero":   0,
+		"total":  12.5,
+		"ids":    []int{1, 2},
+		"none":   []int{},
+		"active": true,
+		"user":   map[string]interface{}{"name": "amy", "age": uint8(30)},
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"id", true},
+		{"nilID", false},
+		{"missing", false},
+		{"name", true},
+		{"empty", false},
+		{"zero", false},
+		{"ids", true},
+		{"none", false},
+		{"id != null", true},
+		{"nilID == null", true},
+		{"missing == nil", true},
+		{"null == null", true},
+		{"id == 7", true},
+		{"total > 12", true},
+		{"total gte 12.5", true},
+		{"total lt 12.5", false},
+		{"zero <= -1", false},
+		{"name == 'bob'", true},
+		{`name == "bob"`, true},
+		{"name != 'it\\'s'", true},
+		{"empty == ''", true},
+		{"name < 'carl'", true},
+		{"active == true", true},
+		{"active != false", true},
+		{"name == 3", false},
+		{"name != 3", true},
+		{"user.name == 'amy'", true},
+		{"user.age >= 30", true},
+		{"user.missing == null", true},
+		{"id and name", true},
+		{"id && empty", false},
+		{"empty or zero or name", true},
+		{"empty || zero", false},
+		{"not empty", true},
+		{"!active", false},
+		{"not (empty or zero)", true},
+		{"id AND name == 'bob' OR empty", true},
+		{"empty and name or active", true},
+		{"empty and (name or active)", false},
+	}
+	for _, tt := range tests {
+		e, err := parseTestExpr(tt.expr)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		v, err := e(&renderContext{params: params, naming: DefaultNaming})
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if truthy(v) != tt.want {
+			t.Errorf("%s = %v, want %v", tt.expr, truthy(v), tt.want)
+		}
+	}
+}
+
+func TestTestExprErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{"", "unexpected end"},
+		{"name ==", "unexpected end"},
+		{"name == 'bob", "unterminated string"},
+		{"(name", "missing )"},
+		{"name)", `unexpected ")"`},
+		{"name = 1", `unexpected '='`},
+		{"and name", `unexpected "and"`},
+		{"1.2.3 > 1", "bad number"},
+		{"name name", `unexpected "name"`},
+	}
+	for _, tt := range tests {
+		_, err := parseTestExpr(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%q: got error %v, want %q", tt.expr, err, tt.err)
+		}
+	}
+	e, err := parseTestExpr("name > 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e(&renderContext{params: map[string]interface{}{"name": "bob"}, naming: DefaultNaming}); err == nil {
+		t.Error("comparing a string and a number with > should fail")
+	}
+}
+
+const renderMapperXML = `<?xml version="1.0"?>
+<mapper namespace="order">
+	<sql id="columns">id, user_id, status</sql>
+	<sql id="byUser">user_id = #{UserID}</sql>
+	<select id="find">
+		SELECT <include refid="columns"/> FROM orders
+		<where>
+			<if test="UserID != null">AND <include refid="byUser"/></if>
+			<if test="Status">AND status IN
+				<foreach collection="Status" item="s" open="(" separator=", " close=")">#{s}</foreach>
+			</if>
+			<if test="Min gte 10 and not Skip">AND total &gt;= #{Min}</if>
+			<if test="Name != ''">OR name = 'a  b'</if>
+		</where>
+		ORDER BY id
+	</select>
+	<select id="byIDs">SELECT * FROM orders WHERE id IN (#{IDs})</select>
+	<insert id="add">
+		INSERT INTO order_items (order_id, sku) VALUES
+		<foreach collection="Items" item="item" index="i" separator=", ">(#{i}, #{item.SKU})</foreach>
+	</insert>
+	<update id="rename">
+		UPDATE orders <set><if test="name">name = #{name},</if> updated = 1,</set> WHERE id = #{id}
+	</update>
+</mapper>`
+
+type renderParams struct {
+	UserID *int64 `field:"user_id"`
+	Status []int
+	Min    int
+	Skip   bool
+	Name   string
+	IDs    []int
+	Items  []renderItem
+}
+
+type renderItem struct {
+	SKU string
+}
+
+func TestMapperRender(t *testing.T) {
+	mp, err := LoadMapperFS(fstest.MapFS{"mappers/order.xml": {Data: []byte(renderMapperXML)}}, "mappers/*.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"order.add", "order.byIDs", "order.find", "order.rename"}
+	if !reflect.DeepEqual(mp.Statements(), want) {
+		t.Fatalf("statements %v, want %v", mp.Statements(), want)
+	}
+	uid := int64(7)
+	tests := []struct {
+		name   string
+		params interface{}
+		query  string
+		args   []interface{}
+	}{
+		{
+			name:   "order.find",
+			params: &renderParams{UserID: &uid, Status: []int{1, 2}, Min: 20, Name: "x"},
+			query:  "SELECT id, user_id, status FROM orders WHERE user_id = ? AND status IN (?, ?) AND total >= ? OR name = 'a  b' ORDER BY id",
+			args:   []interface{}{&uid, 1, 2, 20},
+		},
+		{
+			name:   "order.find",
+			params: renderParams{},
+			query:  "SELECT id, user_id, status FROM orders ORDER BY id",
+		},
+		{
+			name:   "order.find",
+			params: renderParams{Min: 10, Skip: true, Name: "x"},
+			query:  "SELECT id, user_id, status FROM orders WHERE name = 'a  b' ORDER BY id",
+		},
+		{
+			name:   "order.find",
+			params: map[string]interface{}{"Min": 10, "Name": ""},
+			query:  "SELECT id, user_id, status FROM orders WHERE total >= ? ORDER BY id",
+			args:   []interface{}{10},
+		},
+		{
+			name:   "order.byIDs",
+			params: renderParams{IDs: []int{4, 5, 6}},
+			query:  "SELECT * FROM orders WHERE id IN (?)",
+			args:   []interface{}{[]int{4, 5, 6}},
+		},
+		{
+			name:   "order.add",
+			params: renderParams{Items: []renderItem{{"a"}, {"b"}}},
+			query:  "INSERT INTO order_items (order_id, sku) VALUES (?, ?), (?, ?)",
+			args:   []interface{}{0, "a", 1, "b"},
+		},
+		{
+			name:   "order.rename",
+			params: map[string]interface{}{"name": "n", "id": 3},
+			query:  "UPDATE orders SET name = ?, updated = 1 WHERE id = ?",
+			args:   []interface{}{"n", 3},
+		},
+		{
+			name:   "order.rename",
+			params: map[string]interface{}{"id": 3},
+			query:  "UPDATE orders SET updated = 1 WHERE id = ?",
+			args:   []interface{}{3},
+		},
+	}
+	for _, tt := range tests {
+		query, args, err := mp.Render(tt.name, tt.params)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if query != tt.query {
+			t.Errorf("%s:\n got %s\nwant %s", tt.name, query, tt.query)
+		}
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s: args %v, want %v", tt.name, args, tt.args)
+		}
+	}
+
+	if _, _, err := mp.Render("order.rename", map[string]interface{}{}); err == nil || !strings.Contains(err.Error(), "id") {
+		t.Errorf("missing parameter: got error %v", err)
+	}
+	if _, _, err := mp.Render("order.missing", nil); err == nil || !strings.Contains(err.Error(), "no mapper statement") {
+		t.Errorf("missing statement: got error %v", err)
+	}
+}
+
+func TestMapperLoadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		err   string
+	}{
+		{
+			name:  "no files",
+			files: fstest.MapFS{},
+			err:   "no mapper files",
+		},
+		{
+			name:  "root element",
+			files: fstest.MapFS{"a.xml": {Data: []byte(`<queries namespace="a"></queries>`)}},
+			err:   "want <mapper>",
+		},
+		{
+			name: "duplicate statement",
+			files: fstest.MapFS{
+				"a.xml": {Data: []byte(`<mapper namespace="a"><select id="x">SELECT 1</select></mapper>`)},
+				"b.xml": {Data: []byte(`<mapper namespace="a"><select id="x">SELECT 2</select></mapper>`)},
+			},
+			err: "already defined",
+		},
+		{
+			name:  "unknown include",
+			files: fstest.MapFS{"a.xml": {Data: []byte(`<mapper namespace="a"><select id="x"><include refid="nope"/></select></mapper>`)}},
+			err:   "nope",
+		},
+		{
+			name: "include cycle",
+			files: fstest.MapFS{"a.xml": {Data: []byte(`<mapper namespace="a">
+				<sql id="p"><include refid="q"/></sql>
+				<sql id="q"><include refid="p"/></sql>
+				<select id="x"><include refid="p"/></select>
+			</mapper>`)}},
+			err: "cycle",
+		},
+		{
+			name:  "bad test",
+			files: fstest.MapFS{"a.xml": {Data: []byte(`<mapper namespace="a"><select id="x"><if test="a ==">1</if></select></mapper>`)}},
+			err:   "unexpected end",
+		},
+	}
+	for _, tt := range tests {
+		_, err := LoadMapperFS(tt.files, "*.xml")
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: got error %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestNamedWithoutMapper(t *testing.T) {
+	if _, _, err := (&Tx{}).Named("order.find", nil).SQL(); err == nil || !strings.Contains(err.Error(), "no mapper") {
+		t.Fatalf("got error %v", err)
+	}
+}
@@ -0,0 +1,450 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+type fieldInfo struct {
+	Index   []int
+	Name    string
+	Column  string
+	Type    reflect.Type
+	options map[string]string
+}
+
+func (f *fieldInfo) has(option string) bool {
+	_, ok := f.options[strings.ToLower(option)]
+	return ok
+}
+
+// option returns the value of an option written as name:value.
+func (f *fieldInfo) option(name string) string {
+	return f.options[strings.ToLower(name)]
+}
+
+type modelInfo struct {
+	Type    reflect.Type
+	Fields  []*fieldInfo
+	columns map[string]*fieldInfo
+	names   map[string]*fieldInfo
+	naming  NamingStrategy
+
+	pks         []*fieldInfo
+	auto        *fieldInfo
+	softDelete  *fieldInfo
+	version     *fieldInfo
+	createTimes []*fieldInfo
+	updateTimes []*fieldInfo
+	snapshot    []int
+	relations   map[string]*relationInfo
+	err         error
+}
+
+// Tabler lets a model choose its table name. Without it the naming strategy
+// is applied to the struct name.
+type Tabler interface {
+	TableName() string
+}
+
+type modelKey struct {
+	t      reflect.Type
+	naming interface{}
+}
+
+// newModelKey keys the caches by type and naming strategy. A strategy that
+// cannot be a map key, such as a struct holding a map, is keyed by its type,
+// so all values of that type share the cached mapping.
+func newModelKey(t reflect.Type, naming NamingStrategy) modelKey {
+	if nt := reflect.TypeOf(naming); nt != nil && !nt.Comparable() {
+		return modelKey{t, nt}
+	}
+	return modelKey{t, naming}
+}
+
+var modelCache sync.Map
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// getModelInfo parses the `field` tags of a struct type. A tag has the form
+// `field:"column,option,..."`; an empty column falls back to the naming
+// strategy and `field:"-"` skips the field. Options are matched
+// case-insensitively:
+//
+//	pk             primary key
+//	autoIncrement  filled from LastInsertId after an insert
+//	omitempty      left out of inserts when zero
+//	softDelete     deletion timestamp, see DeleteModel
+//	version        optimistic lock counter, see UpdateModel
+//	autoCreateTime set on insert when zero
+//	autoUpdateTime set on insert when zero and on every update
+//
+// The timestamp options take :milli for millisecond precision. Integer
+// fields get a Unix time in seconds, or in milliseconds with :milli. Times
+// are in the connection's zone, see Location.
+//
+// An embedded or exported Snapshot is recorded rather than mapped, and so
+// are fields with a `relation` tag, see relationInfo. An unexported Snapshot
+// field cannot be set and is reported when the model is loaded or updated.
+func getModelInfo(t reflect.Type, naming NamingStrategy) *modelInfo {
+	key := newModelKey(t, naming)
+	if mi, ok := modelCache.Load(key); ok {
+		return mi.(*modelInfo)
+	}
+	mi := &modelInfo{
+		Type:    t,
+		columns: make(map[string]*fieldInfo),
+		names:   make(map[string]*fieldInfo),
+		naming:  naming,
+	}
+	mi.parse(t, nil)
+	modelCache.Store(key, mi)
+	return mi
+}
+
+func (mi *modelInfo) parse(t reflect.Type, index []int) {
+	for n := 0; n < t.NumField(); n++ {
+		sf := t.Field(n)
+		tag := sf.Tag.Get("field")
+		if tag == "-" {
+			continue
+		}
+		idx := make([]int, len(index)+1)
+		copy(idx, index)
+		idx[len(index)] = n
+
+		if sf.Type == snapshotType {
+			if sf.PkgPath != "" {
+				mi.err = fmt.Errorf("mysql: %s.%s: a Snapshot must be embedded or exported", t, sf.Name)
+				continue
+			}
+			mi.snapshot = idx
+			continue
+		}
+		if sf.Anonymous && tag == "" && sf.Type.Kind() == reflect.Struct && sf.Type != timeType {
+			mi.parse(sf.Type, idx)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if rel := sf.Tag.Get("relation"); rel != "" {
+			mi.parseRelation(sf, idx, rel)
+			continue
+		}
+
+		f := &fieldInfo{
+			Index:   idx,
+			Name:    sf.Name,
+			Type:    sf.Type,
+			options: make(map[string]string),
+		}
+		parts := strings.Split(tag, ",")
+		f.Column = strings.TrimSpace(parts[0])
+		for _, opt := range parts[1:] {
+			if opt = strings.TrimSpace(opt); opt != "" {
+				name, value, _ := strings.Cut(opt, ":")
+				f.options[strings.ToLower(name)] = strings.ToLower(value)
+			}
+		}
+		if f.Column == "" {
+			f.Column = mi.naming.ColumnName(sf.Name)
+		}
+		mi.Fields = append(mi.Fields, f)
+		if f.has("pk") {
+			mi.pks = append(mi.pks, f)
+		}
+		if f.has("autoIncrement") && mi.auto == nil {
+			mi.auto = f
+		}
+		if f.has("softDelete") && mi.softDelete == nil {
+			mi.softDelete = f
+		}
+		if f.has("version") && mi.version == nil {
+			mi.version = f
+		}
+		if f.has("autoCreateTime") {
+			mi.createTimes = append(mi.createTimes, f)
+		}
+		if f.has("autoUpdateTime") {
+			mi.updateTimes = append(mi.updateTimes, f)
+		}
+		if _, ok := mi.columns[strings.ToLower(f.Column)]; !ok {
+			mi.columns[strings.ToLower(f.Column)] = f
+		}
+		if _, ok := mi.names[strings.ToLower(f.Name)]; !ok {
+			mi.names[strings.ToLower(f.Name)] = f
+		}
+	}
+}
+
+func (mi *modelInfo) tableName(v reflect.Value) string {
+	if t, ok := v.Interface().(Tabler); ok {
+		return t.TableName()
+	}
+	if v.CanAddr() {
+		if t, ok := v.Addr().Interface().(Tabler); ok {
+			return t.TableName()
+		}
+	}
+	return mi.naming.TableName(mi.Type.Name())
+}
+
+// lookup finds the field for a result column. Column names are compared
+// case-insensitively, as MySQL does.
+func (mi *modelInfo) lookup(column string) *fieldInfo {
+	if f, ok := mi.columns[strings.ToLower(column)]; ok {
+		return f
+	}
+	if f, ok := mi.names[strings.ToLower(mi.naming.FieldName(column))]; ok {
+		return f
+	}
+	return nil
+}
+
+func isIntegerKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// setFieldValue converts value into field, reading times in loc. Only a
+// failing sql.Scanner is an error; other values that do not convert leave the
+// field unchanged.
+func setFieldValue(field reflect.Value, value interface{}, loc *time.Location) error {
+	if field.CanAddr() {
+		if s, ok := field.Addr().Interface().(sql.Scanner); ok {
+			return s.Scan(value)
+		}
+	}
+	if field.Kind() == reflect.Ptr {
+		if value == nil {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		v := reflect.New(field.Type().Elem())
+		if err := setFieldValue(v.Elem(), value, loc); err != nil {
+			return err
+		}
+		field.Set(v)
+		return nil
+	}
+	switch field.Type().Kind() {
+	case reflect.Bool:
+		if v, ok := value.(bool); ok {
+			field.SetBool(v)
+		} else {
+			v, _ := StrTo(ToStr(value)).Bool()
+			field.SetBool(v)
+		}
+	case reflect.String:
+		field.SetString(ToStr(value))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		val := reflect.ValueOf(value)
+		switch val.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			field.SetInt(val.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			field.SetInt(int64(val.Uint()))
+		default:
+			v, _ := StrTo(ToStr(value)).Int64()
+			field.SetInt(v)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val := reflect.ValueOf(value)
+		switch val.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			field.SetUint(uint64(val.Int()))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			field.SetUint(val.Uint())
+		default:
+			v, _ := StrTo(ToStr(value)).Uint64()
+			field.SetUint(v)
+		}
+	case reflect.Float64, reflect.Float32:
+		val := reflect.ValueOf(value)
+		switch val.Kind() {
+		case reflect.Float64:
+			field.SetFloat(val.Float())
+		default:
+			v, _ := StrTo(ToStr(value)).Float64()
+			field.SetFloat(v)
+		}
+	case reflect.Struct:
+		var str string
+		switch d := value.(type) {
+		case time.Time:
+			field.Set(reflect.ValueOf(d.In(loc)))
+		case []byte:
+			str = string(d)
+		case string:
+			str = d
+		}
+		if str != "" {
+			if len(str) >= 19 {
+				str = str[:19]
+				t, err := time.ParseInLocation(format_DateTime, str, loc)
+				if err == nil {
+					field.Set(reflect.ValueOf(t))
+				}
+			} else if len(str) >= 10 {
+				str = str[:10]
+				t, err := time.ParseInLocation(format_Date, str, loc)
+				if err == nil {
+					field.Set(reflect.ValueOf(t))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// modelScanner scans rows into structs, reusing its scan buffers between rows.
+type modelScanner struct {
+	mi       *modelInfo
+	exec     Executor
+	cols     []string
+	fields   []*fieldInfo
+	values   []interface{}
+	scanArgs []interface{}
+}
+
+func newModelScanner(rows *sql.Rows, mi *modelInfo, exec Executor) (*modelScanner, error) {
+	if mi.err != nil {
+		return nil, mi.err
+	}
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	s := &modelScanner{
+		mi:       mi,
+		exec:     exec,
+		cols:     cols,
+		fields:   make([]*fieldInfo, len(cols)),
+		values:   make([]interface{}, len(cols)),
+		scanArgs: make([]interface{}, len(cols)),
+	}
+	for i, c := range cols {
+		s.fields[i] = mi.lookup(c)
+		s.scanArgs[i] = &s.values[i]
+	}
+	return s, nil
+}
+
+// scan decodes the current row into model and runs AfterFind. Decode errors
+// go through the ErrorMapper; hook errors are returned as the hook gave them.
+func (s *modelScanner) scan(rows *sql.Rows, model reflect.Value) error {
+	for i := range s.values {
+		s.values[i] = nil
+	}
+	if err := rows.Scan(s.scanArgs...); err != nil {
+		return s.exec.mysql().convertError(err)
+	}
+	for i, f := range s.fields {
+		if f == nil || s.values[i] == nil {
+			continue
+		}
+		if err := setFieldValue(model.FieldByIndex(f.Index), s.values[i], s.exec.mysql().Location()); err != nil {
+			return s.exec.mysql().convertError(fmt.Errorf("mysql: column %s: %w", s.cols[i], err))
+		}
+	}
+	s.mi.takeSnapshot(model)
+	return afterFind(s.exec, model)
+}
+
+// appendModels scans every row into a new element appended to sliceValue,
+// a slice of structs or pointers to structs.
+func appendModels(rows *sql.Rows, sliceValue reflect.Value, mi *modelInfo, exec Executor) error {
+	isPtr := sliceValue.Type().Elem().Kind() == reflect.Ptr
+
+	scanner, err := newModelScanner(rows, mi, exec)
+	if err != nil {
+		return exec.mysql().convertError(err)
+	}
+	for rows.Next() {
+		resultPtr := reflect.New(mi.Type)
+		result := reflect.Indirect(resultPtr)
+		if err := scanner.scan(rows, result); err != nil {
+			return err
+		}
+
+		if isPtr {
+			sliceValue.Set(reflect.Append(sliceValue, resultPtr))
+		} else {
+			sliceValue.Set(reflect.Append(sliceValue, result))
+		}
+	}
+	return exec.mysql().convertError(rows.Err())
+}
+
+// modelSlice returns the slice that models points to and its struct type.
+func modelSlice(models interface{}) (reflect.Value, reflect.Type, error) {
+	v := reflect.ValueOf(models)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return reflect.Value{}, nil, fmt.Errorf("mysql: models must be a pointer to slice, got %T", models)
+	}
+	elemType := v.Elem().Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return reflect.Value{}, nil, fmt.Errorf("mysql: models must be a slice of structs, got %T", models)
+	}
+	return v.Elem(), elemType, nil
+}
+
+// modelValue returns the struct that model points to.
+func modelValue(model interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("mysql: model must be a non-nil pointer to struct, got %T", model)
+	}
+	return v.Elem(), nil
+}
+
+func quoteIdent(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = "`" + strings.Replace(part, "`", "``", -1) + "`"
+	}
+	return strings.Join(parts, ".")
+}
+
+func placeholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat("?, ", n-1) + "?"
+}
+
+// MapToModel decodes a row returned by QueryForMap into a struct pointer.
+func (m *Mysql) MapToModel(data map[string]interface{}, model interface{}) error {
+	v, err := modelValue(model)
+	if err != nil {
+		return err
+	}
+	mi := getModelInfo(v.Type(), m.NamingStrategy())
+	if mi.err != nil {
+		return mi.err
+	}
+	for key, value := range data {
+		if value == nil {
+			continue
+		}
+		if f := mi.lookup(key); f != nil {
+			if err := setFieldValue(v.FieldByIndex(f.Index), value, m.Location()); err != nil {
+				return fmt.Errorf("mysql: column %s: %w", key, err)
+			}
+		}
+	}
+	mi.takeSnapshot(v)
+	return nil
+}
@@ -3,7 +3,7 @@ package mysql
 import (
 	"database/sql"
 	"reflect"
-	"strings"
+	"sync"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -13,6 +13,16 @@ import (
 type Mysql struct {
 	conn    *sql.DB
 	connStr string
+	naming  NamingStrategy
+
+	notFoundError bool
+	errorMapper   ErrorMapper
+	loc           *time.Location
+
+	versionMu sync.Mutex
+	version   string
+
+	mapper *Mapper
 }
 
 func NewMysql() *Mysql {
@@ -23,36 +33,18 @@ func NewMysql() *Mysql {
 func (this *Mysql) BeginTx() (*Tx, error) {
 	tx, errTx := this.conn.Begin()
 	if errTx != nil {
-		tx.Rollback()
-		return nil, errTx
+		return nil, this.convertError(errTx)
 	}
-	return &Tx{Tx: tx, hasError: false}, nil
+	return &Tx{Tx: tx, hasError: false, db: this}, nil
 }
 
-///**
-//处理数据库错误，记录日志，回滚事务，并返回错误代码 系统异常
-//baisu 2015-07-30
-//*/
-//func (this *Mysql) ErrorHandlerTx(tx *sql.Tx, err interface{}) error {
-//	tx.Rollback()
-//	log.Error(err)
-//	return errors.New(Error(constant.ERR_DATABASE_FAULT))
-//}
-//
-///**
-//处理数据库错误，记录日志，并返回错误代码 系统异常
-//baisu 2015-07-30
-//*/
-//func (this *Mysql) ErrorHandler(err interface{}) error {
-//	log.Error(err)
-//	return errors.New(Error(constant.ERR_DATABASE_FAULT))
-//}
-
 func (m *Mysql) Open(dbConn string, maxIdle int, maxConns int) error {
-	m.connStr = dbConn
+	if err := m.setConnStr(dbConn); err != nil {
+		return m.convertError(err)
+	}
 	conn, err := sql.Open("mysql", dbConn)
 	if err != nil {
-		return err
+		return m.convertError(err)
 	}
 	conn.SetMaxIdleConns(maxIdle)
 	conn.SetMaxOpenConns(maxConns)
@@ -61,10 +53,12 @@ func (m *Mysql) Open(dbConn string, maxIdle int, maxConns int) error {
 }
 
 func (m *Mysql) OpenOne(dbConn string) error {
-	m.connStr = dbConn
+	if err := m.setConnStr(dbConn); err != nil {
+		return m.convertError(err)
+	}
 	conn, err := sql.Open("mysql", dbConn)
 	if err != nil {
-		return err
+		return m.convertError(err)
 	}
 	m.conn = conn
 	return nil
@@ -80,45 +74,57 @@ func (m *Mysql) GetConnection() *sql.DB {
 }
 
 func (m *Mysql) Insert(query string, args ...interface{}) (int64, error) {
-	stmt, err := m.conn.Prepare(query)
+	query, args, err := m.bind(query, args)
 	if err != nil {
 		return -1, err
 	}
+	stmt, err := m.conn.Prepare(query)
+	if err != nil {
+		return -1, m.convertError(err)
+	}
 	defer stmt.Close()
 
 	res, err := stmt.Exec(args...)
 	if err != nil {
-		return -1, err
+		return -1, m.convertError(err)
 	}
 	return res.LastInsertId()
 }
 
 func (m *Mysql) Delete(query string, args ...interface{}) (int64, error) {
-	stmt, err := m.conn.Prepare(query)
+	query, args, err := m.bind(query, args)
 	if err != nil {
 		return -1, err
 	}
+	stmt, err := m.conn.Prepare(query)
+	if err != nil {
+		return -1, m.convertError(err)
+	}
 	defer stmt.Close()
 
 	res, err := stmt.Exec(args...)
 	if err != nil {
-		return -1, err
+		return -1, m.convertError(err)
 	}
 	return res.RowsAffected()
 }
 
 func (m *Mysql) InsertTx(tx *Tx, query string, args ...interface{}) (int64, error) {
+	query, args, err := m.bind(query, args)
+	if err != nil {
+		return -1, err
+	}
 	stmt, err := tx.Tx.Prepare(query)
 	if err != nil {
 		tx.ErrorHappen()
-		return -1, err
+		return -1, m.convertError(err)
 	}
 	defer stmt.Close()
 
 	res, err := stmt.Exec(args...)
 	if err != nil {
 		tx.ErrorHappen()
-		return -1, err
+		return -1, m.convertError(err)
 	}
 	return res.LastInsertId()
 }
@@ -127,14 +133,19 @@ func (m *Mysql) TranBatchExec(querys []string, args [][]interface{}) error {
 	tx, err := m.conn.Begin()
 	if err != nil {
 
-		return err
+		return m.convertError(err)
 	}
 	for i, query := range querys {
-		_, err = tx.Exec(query, args[i]...)
+		query, queryArgs, err := m.bind(query, args[i])
 		if err != nil {
 			tx.Rollback()
 			return err
 		}
+		_, err = tx.Exec(query, queryArgs...)
+		if err != nil {
+			tx.Rollback()
+			return m.convertError(err)
+		}
 	}
 	tx.Commit()
 	return nil
@@ -152,58 +163,70 @@ func MakeBatchPack(pack *BatchPack, query string, args ...interface{}) *BatchPac
 }
 
 func (m *Mysql) Update(query string, args ...interface{}) (int64, error) {
-	stmt, err := m.conn.Prepare(query)
+	query, args, err := m.bind(query, args)
 	if err != nil {
 		return -1, err
 	}
+	stmt, err := m.conn.Prepare(query)
+	if err != nil {
+		return -1, m.convertError(err)
+	}
 	defer stmt.Close()
 
 	res, err := stmt.Exec(args...)
 	if err != nil {
-		return -1, err
+		return -1, m.convertError(err)
 	}
 	return res.RowsAffected()
 }
 
 func (m *Mysql) UpdateTx(tx *Tx, query string, args ...interface{}) (int64, error) {
+	query, args, err := m.bind(query, args)
+	if err != nil {
+		return -1, err
+	}
 	stmt, err := tx.Tx.Prepare(query)
 	if err != nil {
 		tx.ErrorHappen()
-		return -1, err
+		return -1, m.convertError(err)
 	}
 	defer stmt.Close()
 
 	res, err := stmt.Exec(args...)
 	if err != nil {
 		tx.ErrorHappen()
-		return -1, err
+		return -1, m.convertError(err)
 	}
 	return res.RowsAffected()
 }
 
 //存储过程查询，返回值为单行内容，目前项目不要使用
 func (m *Mysql) ProcForMap(query string, args ...interface{}) (map[string]interface{}, error) {
-	conn, err := sql.Open("mysql", m.connStr)
+	query, args, err := m.bind(query, args)
 	if err != nil {
 		return nil, err
 	}
+	conn, err := sql.Open("mysql", m.connStr)
+	if err != nil {
+		return nil, m.convertError(err)
+	}
 	defer conn.Close()
 
 	stmt, err := conn.Prepare(query)
 	if err != nil {
-		return nil, err
+		return nil, m.convertError(err)
 	}
 	defer stmt.Close()
 
 	rows, err := stmt.Query(args...)
 	if err != nil {
-		return nil, err
+		return nil, m.convertError(err)
 	}
 	defer rows.Close()
 
 	cols, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return nil, m.convertError(err)
 	}
 
 	values := make([]interface{}, len(cols))
@@ -215,7 +238,7 @@ func (m *Mysql) ProcForMap(query string, args ...interface{}) (map[string]interf
 
 	if rows.Next() {
 		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, err
+			return nil, m.convertError(err)
 		}
 		result := make(map[string]interface{}, len(cols))
 		for ii, key := range cols {
@@ -236,27 +259,31 @@ func (m *Mysql) ProcForMap(query string, args ...interface{}) (map[string]interf
 
 //存储过程查询，返回值为多行内容，目前项目不要使用
 func (m *Mysql) ProcForMapSlice(query string, args ...interface{}) ([]map[string]interface{}, error) {
-	conn, err := sql.Open("mysql", m.connStr)
+	query, args, err := m.bind(query, args)
 	if err != nil {
 		return nil, err
 	}
+	conn, err := sql.Open("mysql", m.connStr)
+	if err != nil {
+		return nil, m.convertError(err)
+	}
 	defer conn.Close()
 
 	stmt, err := conn.Prepare(query)
 	if err != nil {
-		return nil, err
+		return nil, m.convertError(err)
 	}
 	defer stmt.Close()
 
 	rows, err := stmt.Query(args...)
 	if err != nil {
-		return nil, err
+		return nil, m.convertError(err)
 	}
 	defer rows.Close()
 
 	cols, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return nil, m.convertError(err)
 	}
 
 	values := make([]interface{}, len(cols))
@@ -268,7 +295,7 @@ func (m *Mysql) ProcForMapSlice(query string, args ...interface{}) ([]map[string
 	var results []map[string]interface{}
 	for rows.Next() {
 		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, err
+			return nil, m.convertError(err)
 		}
 		result := make(map[string]interface{}, len(cols))
 		for ii, key := range cols {
@@ -288,21 +315,25 @@ func (m *Mysql) ProcForMapSlice(query string, args ...interface{}) ([]map[string
 }
 
 func (m *Mysql) QueryForMap(query string, args ...interface{}) (map[string]interface{}, error) {
-	stmt, err := m.conn.Prepare(query)
+	query, args, err := m.bind(query, args)
 	if err != nil {
 		return nil, err
 	}
+	stmt, err := m.conn.Prepare(query)
+	if err != nil {
+		return nil, m.convertError(err)
+	}
 	defer stmt.Close()
 
 	rows, err := stmt.Query(args...)
 	if err != nil {
-		return nil, err
+		return nil, m.convertError(err)
 	}
 	defer rows.Close()
 
 	cols, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return nil, m.convertError(err)
 	}
 
 	values := make([]interface{}, len(cols))
@@ -314,7 +345,7 @@ func (m *Mysql) QueryForMap(query string, args ...interface{}) (map[string]inter
 
 	if rows.Next() {
 		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, err
+			return nil, m.convertError(err)
 		}
 		result := make(map[string]interface{}, len(cols))
 		for ii, key := range cols {
@@ -330,25 +361,29 @@ func (m *Mysql) QueryForMap(query string, args ...interface{}) (map[string]inter
 		}
 		return result, nil
 	}
-	return nil, nil
+	return nil, m.notFound()
 }
 
 func (m *Mysql) QueryForMapUint642Str(query string, args ...interface{}) (map[string]interface{}, error) {
-	stmt, err := m.conn.Prepare(query)
+	query, args, err := m.bind(query, args)
 	if err != nil {
 		return nil, err
 	}
+	stmt, err := m.conn.Prepare(query)
+	if err != nil {
+		return nil, m.convertError(err)
+	}
 	defer stmt.Close()
 
 	rows, err := stmt.Query(args...)
 	if err != nil {
-		return nil, err
+		return nil, m.convertError(err)
 	}
 	defer rows.Close()
 
 	cols, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return nil, m.convertError(err)
 	}
 
 	values := make([]interface{}, len(cols))
@@ -360,7 +395,7 @@ func (m *Mysql) QueryForMapUint642Str(query string, args ...interface{}) (map[st
 
 	if rows.Next() {
 		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, err
+			return nil, m.convertError(err)
 		}
 		result := make(map[string]interface{}, len(cols))
 		for ii, key := range cols {
@@ -380,25 +415,29 @@ func (m *Mysql) QueryForMapUint642Str(query string, args ...interface{}) (map[st
 		}
 		return result, nil
 	}
-	return nil, nil
+	return nil, m.notFound()
 }
 
 func (m *Mysql) QueryForMapU642StrSlice(query string, args ...interface{}) ([]map[string]interface{}, error) {
-	stmt, err := m.conn.Prepare(query)
+	query, args, err := m.bind(query, args)
 	if err != nil {
 		return nil, err
 	}
+	stmt, err := m.conn.Prepare(query)
+	if err != nil {
+		return nil, m.convertError(err)
+	}
 	defer stmt.Close()
 
 	rows, err := stmt.Query(args...)
 	if err != nil {
-		return nil, err
+		return nil, m.convertError(err)
 	}
 	defer rows.Close()
 
 	cols, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return nil, m.convertError(err)
 	}
 
 	values := make([]interface{}, len(cols))
@@ -411,7 +450,7 @@ func (m *Mysql) QueryForMapU642StrSlice(query string, args ...interface{}) ([]ma
 	var results []map[string]interface{}
 	for rows.Next() {
 		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, err
+			return nil, m.convertError(err)
 		}
 		result := make(map[string]interface{}, len(cols))
 		for ii, key := range cols {
@@ -435,10 +474,14 @@ func (m *Mysql) QueryForMapU642StrSlice(query string, args ...interface{}) ([]ma
 }
 
 func (m *Mysql) QueryForMapTx(tx *Tx, query string, args ...interface{}) (map[string]interface{}, error) {
+	query, args, err := m.bind(query, args)
+	if err != nil {
+		return nil, err
+	}
 	stmt, err := tx.Tx.Prepare(query)
 	if err != nil {
 		tx.ErrorHappen()
-		return nil, err
+		return nil, m.convertError(err)
 	}
 
 	defer stmt.Close()
@@ -446,7 +489,7 @@ func (m *Mysql) QueryForMapTx(tx *Tx, query string, args ...interface{}) (map[st
 	rows, err := stmt.Query(args...)
 	if err != nil {
 		tx.ErrorHappen()
-		return nil, err
+		return nil, m.convertError(err)
 	}
 
 	defer rows.Close()
@@ -454,7 +497,7 @@ func (m *Mysql) QueryForMapTx(tx *Tx, query string, args ...interface{}) (map[st
 	cols, err := rows.Columns()
 	if err != nil {
 		tx.ErrorHappen()
-		return nil, err
+		return nil, m.convertError(err)
 	}
 
 	values := make([]interface{}, len(cols))
@@ -467,7 +510,7 @@ func (m *Mysql) QueryForMapTx(tx *Tx, query string, args ...interface{}) (map[st
 	if rows.Next() {
 		if err := rows.Scan(scanArgs...); err != nil {
 			tx.ErrorHappen()
-			return nil, err
+			return nil, m.convertError(err)
 		}
 		result := make(map[string]interface{}, len(cols))
 		for ii, key := range cols {
@@ -483,25 +526,29 @@ func (m *Mysql) QueryForMapTx(tx *Tx, query string, args ...interface{}) (map[st
 		}
 		return result, nil
 	}
-	return nil, nil
+	return nil, m.notFound()
 }
 
 func (m *Mysql) QueryForMapSlice(query string, args ...interface{}) ([]map[string]interface{}, error) {
-	stmt, err := m.conn.Prepare(query)
+	query, args, err := m.bind(query, args)
 	if err != nil {
 		return nil, err
 	}
+	stmt, err := m.conn.Prepare(query)
+	if err != nil {
+		return nil, m.convertError(err)
+	}
 	defer stmt.Close()
 
 	rows, err := stmt.Query(args...)
 	if err != nil {
-		return nil, err
+		return nil, m.convertError(err)
 	}
 	defer rows.Close()
 
 	cols, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return nil, m.convertError(err)
 	}
 
 	values := make([]interface{}, len(cols))
@@ -514,7 +561,7 @@ func (m *Mysql) QueryForMapSlice(query string, args ...interface{}) ([]map[strin
 	var results []map[string]interface{}
 	for rows.Next() {
 		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, err
+			return nil, m.convertError(err)
 		}
 		result := make(map[string]interface{}, len(cols))
 		for ii, key := range cols {
@@ -534,24 +581,28 @@ func (m *Mysql) QueryForMapSlice(query string, args ...interface{}) ([]map[strin
 }
 
 func (m *Mysql) QueryForMapSliceTx(tx *Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	query, args, err := m.bind(query, args)
+	if err != nil {
+		return nil, err
+	}
 	stmt, err := tx.Tx.Prepare(query)
 	if err != nil {
 		tx.ErrorHappen()
-		return nil, err
+		return nil, m.convertError(err)
 	}
 	defer stmt.Close()
 
 	rows, err := stmt.Query(args...)
 	if err != nil {
 		tx.ErrorHappen()
-		return nil, err
+		return nil, m.convertError(err)
 	}
 	defer rows.Close()
 
 	cols, err := rows.Columns()
 	if err != nil {
 		tx.ErrorHappen()
-		return nil, err
+		return nil, m.convertError(err)
 	}
 
 	values := make([]interface{}, len(cols))
@@ -565,7 +616,7 @@ func (m *Mysql) QueryForMapSliceTx(tx *Tx, query string, args ...interface{}) ([
 	for rows.Next() {
 		if err := rows.Scan(scanArgs...); err != nil {
 			tx.ErrorHappen()
-			return nil, err
+			return nil, m.convertError(err)
 		}
 		result := make(map[string]interface{}, len(cols))
 		for ii, key := range cols {
@@ -585,278 +636,54 @@ func (m *Mysql) QueryForMapSliceTx(tx *Tx, query string, args ...interface{}) ([
 }
 
 func (m *Mysql) QueryForModelSlice(model interface{}, query string, args ...interface{}) error {
-	rows, err := m.conn.Query(query, args...)
+	query, args, err := m.bind(query, args)
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
-
-	cols, err := rows.Columns()
+	rows, err := m.conn.Query(query, args...)
 	if err != nil {
-		return err
+		return m.convertError(err)
 	}
+	defer rows.Close()
 
 	sliceValue := reflect.Indirect(reflect.ValueOf(model))
 	sliceElementType := sliceValue.Type().Elem()
 
-	var isPtr bool
 	if sliceElementType.Kind() == reflect.Ptr {
-		isPtr = true
 		sliceElementType = sliceElementType.Elem()
 	}
 
-	colsNum := len(cols)
-	scanArgs := make([]interface{}, colsNum)
-
-	var fieldToStructIndex = make(map[string]int)
-
-	for i, c := range cols {
-		for n := 0; n < sliceElementType.NumField(); n++ {
-			field := sliceElementType.Field(n).Tag.Get("field")
-			if field == "" {
-				if strings.ToLower(c) == strings.ToLower(sliceElementType.Field(n).Name) {
-					fieldToStructIndex[c] = n
-					break
-				}
-			} else {
-				if strings.ToLower(c) == strings.ToLower(field) {
-					fieldToStructIndex[c] = n
-					break
-				}
-			}
-		}
-		var arg interface{}
-		scanArgs[i] = &arg
-	}
-
-	for rows.Next() {
-		if err := rows.Scan(scanArgs...); err != nil {
-			return err
-		}
-		resultPtr := reflect.New(sliceElementType)
-		result := reflect.Indirect(resultPtr)
-
-		for ii, key := range cols {
-			arg := scanArgs[ii]
-			if arg == nil {
-				continue
-			}
-			value := reflect.ValueOf(arg).Elem().Interface()
-
-			if index, ok := fieldToStructIndex[key]; ok {
-				field := result.Field(index)
-				switch field.Type().Kind() {
-				case reflect.Bool:
-					if v, ok := value.(bool); ok {
-						field.SetBool(v)
-					} else {
-						v, _ := StrTo(ToStr(value)).Bool()
-						field.SetBool(v)
-					}
-				case reflect.String:
-					field.SetString(ToStr(value))
-				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-					val := reflect.ValueOf(value)
-					switch val.Kind() {
-					case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-						field.SetInt(val.Int())
-					case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-						field.SetInt(int64(val.Uint()))
-					default:
-						v, _ := StrTo(ToStr(value)).Int64()
-						field.SetInt(v)
-					}
-				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-					val := reflect.ValueOf(value)
-					switch val.Kind() {
-					case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-						field.SetUint(uint64(val.Int()))
-					case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-						field.SetUint(val.Uint())
-					default:
-						v, _ := StrTo(ToStr(value)).Uint64()
-						field.SetUint(v)
-					}
-				case reflect.Float64, reflect.Float32:
-					val := reflect.ValueOf(value)
-					switch val.Kind() {
-					case reflect.Float64:
-						field.SetFloat(val.Float())
-					default:
-						v, _ := StrTo(ToStr(value)).Float64()
-						field.SetFloat(v)
-					}
-				case reflect.Struct:
-					var str string
-					switch d := value.(type) {
-					case time.Time:
-						d = d.In(time.Local)
-						field.Set(reflect.ValueOf(d))
-					case []byte:
-						str = string(d)
-					case string:
-						str = d
-					}
-					if str != "" {
-						if len(str) >= 19 {
-							str = str[:19]
-							t, err := time.ParseInLocation(format_DateTime, str, time.Local)
-							if err == nil {
-								t = t.In(DefaultTimeLoc)
-								field.Set(reflect.ValueOf(t))
-							}
-						} else if len(str) >= 10 {
-							str = str[:10]
-							t, err := time.ParseInLocation(format_Date, str, DefaultTimeLoc)
-							if err == nil {
-								field.Set(reflect.ValueOf(t))
-							}
-						}
-					}
-				}
-			}
-		}
-
-		if isPtr {
-			sliceValue.Set(reflect.Append(sliceValue, resultPtr))
-		} else {
-			sliceValue.Set(reflect.Append(sliceValue, result))
-		}
-	}
-	return nil
+	return appendModels(rows, sliceValue, getModelInfo(sliceElementType, m.NamingStrategy()), m)
 }
 
 func (m *Mysql) QueryForModel(model interface{}, query string, args ...interface{}) (bool, error) {
-	rows, err := m.conn.Query(query, args...)
+	query, args, err := m.bind(query, args)
 	if err != nil {
 		return false, err
 	}
-	defer rows.Close()
-
-	cols, err := rows.Columns()
+	rows, err := m.conn.Query(query, args...)
 	if err != nil {
-		return false, err
+		return false, m.convertError(err)
 	}
+	defer rows.Close()
 
 	modelValue := reflect.Indirect(reflect.ValueOf(model))
-	modelType := modelValue.Type()
-
-	if modelType.Kind() == reflect.Ptr {
-		modelType = modelType.Elem()
-	}
-
-	colsNum := len(cols)
-	scanArgs := make([]interface{}, colsNum)
-
-	var fieldToStructIndex = make(map[string]int)
 
-	for i, c := range cols {
-		for n := 0; n < modelType.NumField(); n++ {
-			field := modelType.Field(n).Tag.Get("field")
-			if field == "" {
-				if strings.ToLower(c) == strings.ToLower(modelType.Field(n).Name) {
-					fieldToStructIndex[c] = n
-					break
-				}
-			} else {
-				if strings.ToLower(c) == strings.ToLower(field) {
-					fieldToStructIndex[c] = n
-					break
-				}
-			}
-		}
-		var arg interface{}
-		scanArgs[i] = &arg
+	scanner, err := newModelScanner(rows, getModelInfo(modelValue.Type(), m.NamingStrategy()), m)
+	if err != nil {
+		return false, m.convertError(err)
 	}
 
 	var b bool
 
 	if rows.Next() {
-		if err := rows.Scan(scanArgs...); err != nil {
+		if err := scanner.scan(rows, modelValue); err != nil {
 			return b, err
 		}
-
-		for ii, key := range cols {
-			arg := scanArgs[ii]
-			if arg == nil {
-				continue
-			}
-			value := reflect.ValueOf(arg).Elem().Interface()
-
-			if index, ok := fieldToStructIndex[key]; ok {
-				field := modelValue.Field(index)
-				switch field.Type().Kind() {
-				case reflect.Bool:
-					if v, ok := value.(bool); ok {
-						field.SetBool(v)
-					} else {
-						v, _ := StrTo(ToStr(value)).Bool()
-						field.SetBool(v)
-					}
-				case reflect.String:
-					field.SetString(ToStr(value))
-				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-					val := reflect.ValueOf(value)
-					switch val.Kind() {
-					case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-						field.SetInt(val.Int())
-					case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-						field.SetInt(int64(val.Uint()))
-					default:
-						v, _ := StrTo(ToStr(value)).Int64()
-						field.SetInt(v)
-					}
-				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-					val := reflect.ValueOf(value)
-					switch val.Kind() {
-					case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-						field.SetUint(uint64(val.Int()))
-					case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-						field.SetUint(val.Uint())
-					default:
-						v, _ := StrTo(ToStr(value)).Uint64()
-						field.SetUint(v)
-					}
-				case reflect.Float64, reflect.Float32:
-					val := reflect.ValueOf(value)
-					switch val.Kind() {
-					case reflect.Float64:
-						field.SetFloat(val.Float())
-					default:
-						v, _ := StrTo(ToStr(value)).Float64()
-						field.SetFloat(v)
-					}
-				case reflect.Struct:
-					var str string
-					switch d := value.(type) {
-					case time.Time:
-						d = d.In(time.Local)
-						field.Set(reflect.ValueOf(d))
-					case []byte:
-						str = string(d)
-					case string:
-						str = d
-					}
-					if str != "" {
-						if len(str) >= 19 {
-							str = str[:19]
-							t, err := time.ParseInLocation(format_DateTime, str, time.Local)
-							if err == nil {
-								t = t.In(DefaultTimeLoc)
-								field.Set(reflect.ValueOf(t))
-							}
-						} else if len(str) >= 10 {
-							str = str[:10]
-							t, err := time.ParseInLocation(format_Date, str, DefaultTimeLoc)
-							if err == nil {
-								field.Set(reflect.ValueOf(t))
-							}
-						}
-					}
-				}
-			}
-		}
 		b = true
 	}
+	if !b {
+		return b, m.notFound()
+	}
 	return b, nil
 }
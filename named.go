@@ -0,0 +1,195 @@
+package mysql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// A query with :name placeholders can be run by any Mysql or Tx method by
+// passing a single map or struct as its only argument:
+//
+//	m.Insert("INSERT INTO users (name, age) VALUES (:name, :age)",
+//		map[string]interface{}{"name": "bob", "age": 30})
+//	m.Update("UPDATE users SET name = :name WHERE id = :id", &user)
+//
+// The query is rewritten to ? placeholders before it is sent. Map keys are
+// matched exactly and every key must be used. Struct fields are matched by
+// column or field name as in the model APIs, and fields not named in the
+// query are ignored. A placeholder with no value is an error. Placeholders
+// inside quotes, backticks and comments are left alone.
+
+// namedSource holds the values behind :name placeholders.
+type namedSource struct {
+	value  reflect.Value
+	mi     *modelInfo
+	unused map[string]bool
+}
+
+// newNamedSource returns false when arg is passed to the driver as is.
+func newNamedSource(arg interface{}, naming NamingStrategy) (*namedSource, bool) {
+	if arg == nil {
+		return nil, false
+	}
+	if _, ok := arg.(driver.Valuer); ok {
+		return nil, false
+	}
+	v := reflect.ValueOf(arg)
+	if v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String {
+		s := &namedSource{value: v, unused: make(map[string]bool, v.Len())}
+		for _, k := range v.MapKeys() {
+			s.unused[k.String()] = true
+		}
+		return s, true
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || v.Type() == timeType {
+		return nil, false
+	}
+	return &namedSource{value: v, mi: getModelInfo(v.Type(), naming)}, true
+}
+
+func (s *namedSource) get(name string) (interface{}, error) {
+	if s.mi != nil {
+		f := s.mi.lookup(name)
+		if f == nil {
+			return nil, fmt.Errorf("mysql: named parameter :%s has no field in %s", name, s.mi.Type)
+		}
+		return s.value.FieldByIndex(f.Index).Interface(), nil
+	}
+	v := s.value.MapIndex(reflect.ValueOf(name).Convert(s.value.Type().Key()))
+	if !v.IsValid() {
+		return nil, fmt.Errorf("mysql: named parameter :%s has no value", name)
+	}
+	delete(s.unused, name)
+	return v.Interface(), nil
+}
+
+func (s *namedSource) check() error {
+	if len(s.unused) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(s.unused))
+	for name := range s.unused {
+		names = append(names, ":"+name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("mysql: named parameters %s are not used by the query", strings.Join(names, ", "))
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || c >= '0' && c <= '9'
+}
+
+// skipQuoted returns the index after the quoted string, identifier or
+// comment starting at i, or i when there is none.
+func skipQuoted(query string, i int) int {
+	switch c := query[i]; {
+	case c == '\'' || c == '"' || c == '`':
+		for j := i + 1; j < len(query); j++ {
+			switch query[j] {
+			case '\\':
+				if c != '`' {
+					j++
+				}
+			case c:
+				return j + 1
+			}
+		}
+		return len(query)
+	case c == '#' || c == '-' && strings.HasPrefix(query[i:], "-- "):
+		if j := strings.IndexByte(query[i:], '\n'); j >= 0 {
+			return i + j + 1
+		}
+		return len(query)
+	case c == '/' && strings.HasPrefix(query[i:], "/*"):
+		if j := strings.Index(query[i+2:], "*/"); j >= 0 {
+			return i + 2 + j + 2
+		}
+		return len(query)
+	}
+	return i
+}
+
+// bindNamed rewrites the :name placeholders of query to ? and returns their
+// values in order.
+func bindNamed(query string, s *namedSource) (string, []interface{}, error) {
+	var sb strings.Builder
+	var args []interface{}
+	positional := false
+	for i := 0; i < len(query); i++ {
+		if j := skipQuoted(query, i); j > i {
+			sb.WriteString(query[i:j])
+			i = j - 1
+			continue
+		}
+		c := query[i]
+		switch {
+		case c == '?':
+			positional = true
+		case c == ':' && i+1 < len(query) && isNameStart(query[i+1]) && (i == 0 || query[i-1] != ':'):
+			j := i + 1
+			for j < len(query) && isNameChar(query[j]) {
+				j++
+			}
+			value, err := s.get(query[i+1 : j])
+			if err != nil {
+				return "", nil, err
+			}
+			args = append(args, value)
+			sb.WriteByte('?')
+			i = j - 1
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	if positional && len(args) > 0 {
+		return "", nil, fmt.Errorf("mysql: query mixes ? and :name placeholders")
+	}
+	return sb.String(), args, s.check()
+}
+
+// bind is applied by every method before query reaches the driver. It binds
+// :name placeholders and expands slice arguments, see expandSlices.
+func (m *Mysql) bind(query string, args []interface{}) (string, []interface{}, error) {
+	if len(args) == 1 {
+		if s, ok := newNamedSource(args[0], m.NamingStrategy()); ok {
+			bound, values, err := bindNamed(query, s)
+			if err != nil {
+				return query, args, err
+			}
+			// a struct argument without placeholders goes to the driver,
+			// which may know how to convert it
+			if len(values) > 0 || s.mi == nil {
+				query, args = bound, values
+			}
+		}
+	}
+	return expandSlices(query, args)
+}
+
+// BindNamed rewrites the :name placeholders of query to ? with values from
+// arg, a map or struct, for use with other libraries or the builder. Slice
+// values are expanded as in the query methods.
+func BindNamed(query string, arg interface{}) (string, []interface{}, error) {
+	s, ok := newNamedSource(arg, DefaultNaming)
+	if !ok {
+		return "", nil, fmt.Errorf("mysql: cannot bind named parameters from %T", arg)
+	}
+	query, args, err := bindNamed(query, s)
+	if err != nil {
+		return "", nil, err
+	}
+	return expandSlices(query, args)
+}
@@ -0,0 +1,153 @@
+package mysql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type namedUser struct {
+	ID      int64  `field:"id"`
+	Name    string `field:"name"`
+	Age     int
+	Created time.Time `field:"created_at"`
+}
+
+func TestBindNamed(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	user := &namedUser{ID: 7, Name: "bob", Age: 30, Created: created}
+	tests := []struct {
+		name  string
+		query string
+		arg   interface{}
+		want  string
+		args  []interface{}
+	}{
+		{
+			name:  "map",
+			query: "INSERT INTO users (name, age) VALUES (:name, :age)",
+			arg:   map[string]interface{}{"name": "bob", "age": 30},
+			want:  "INSERT INTO users (name, age) VALUES (?, ?)",
+			args:  []interface{}{"bob", 30},
+		},
+		{
+			name:  "map reused key",
+			query: "SELECT * FROM t WHERE a = :v OR b = :v",
+			arg:   map[string]interface{}{"v": 1},
+			want:  "SELECT * FROM t WHERE a = ? OR b = ?",
+			args:  []interface{}{1, 1},
+		},
+		{
+			name:  "typed map",
+			query: "SELECT * FROM t WHERE a = :a",
+			arg:   map[string]string{"a": "x"},
+			want:  "SELECT * FROM t WHERE a = ?",
+			args:  []interface{}{"x"},
+		},
+		{
+			name:  "struct by column and field name",
+			query: "UPDATE users SET name = :name, age = :Age, created_at = :created_at WHERE id = :ID",
+			arg:   user,
+			want:  "UPDATE users SET name = ?, age = ?, created_at = ? WHERE id = ?",
+			args:  []interface{}{"bob", 30, created, int64(7)},
+		},
+		{
+			name:  "struct value with unused fields",
+			query: "SELECT * FROM users WHERE id = :id",
+			arg:   *user,
+			want:  "SELECT * FROM users WHERE id = ?",
+			args:  []interface{}{int64(7)},
+		},
+		{
+			name:  "quotes and comments",
+			query: "SELECT ':a', \":a\", `:a`, 'it\\'s :a' -- :a\n# :a\n/* :a */ FROM t WHERE x = :a",
+			arg:   map[string]interface{}{"a": 1},
+			want:  "SELECT ':a', \":a\", `:a`, 'it\\'s :a' -- :a\n# :a\n/* :a */ FROM t WHERE x = ?",
+			args:  []interface{}{1},
+		},
+		{
+			name:  "double colon and assignment",
+			query: "SELECT a::b, @n := :n, '12:30'",
+			arg:   map[string]interface{}{"n": 2},
+			want:  "SELECT a::b, @n := ?, '12:30'",
+			args:  []interface{}{2},
+		},
+		{
+			name:  "slice value",
+			query: "SELECT * FROM t WHERE id IN (:ids) AND s = :s",
+			arg:   map[string]interface{}{"ids": []int{1, 2, 3}, "s": "x"},
+			want:  "SELECT * FROM t WHERE id IN (?, ?, ?) AND s = ?",
+			args:  []interface{}{1, 2, 3, "x"},
+		},
+		{
+			name:  "name at start and end",
+			query: ":a+:b",
+			arg:   map[string]interface{}{"a": 1, "b": 2},
+			want:  "?+?",
+			args:  []interface{}{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		query, args, err := BindNamed(tt.query, tt.arg)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if query != tt.want {
+			t.Errorf("%s:\n got %q\nwant %q", tt.name, query, tt.want)
+		}
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s: args %v, want %v", tt.name, args, tt.args)
+		}
+	}
+}
+
+func TestBindNamedErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		arg   interface{}
+		err   string
+	}{
+		{"missing key", "SELECT :a, :b", map[string]interface{}{"a": 1}, ":b has no value"},
+		{"unused key", "SELECT :a", map[string]interface{}{"a": 1, "b": 2, "c": 3}, ":b, :c are not used"},
+		{"missing field", "SELECT :nope", namedUser{}, ":nope has no field"},
+		{"mixed placeholders", "SELECT ?, :a", map[string]interface{}{"a": 1}, "mixes ? and :name"},
+		{"not a map or struct", "SELECT :a", 3, "cannot bind"},
+		{"nil struct pointer", "SELECT :a", (*namedUser)(nil), "cannot bind"},
+	}
+	for _, tt := range tests {
+		_, _, err := BindNamed(tt.query, tt.arg)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: got error %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestBindPositional(t *testing.T) {
+	m := &Mysql{}
+	tests := []struct {
+		name  string
+		query string
+		args  []interface{}
+		want  string
+		wargs []interface{}
+	}{
+		{"no args", "SELECT 1", nil, "SELECT 1", nil},
+		{"scalar", "SELECT ?", []interface{}{1}, "SELECT ?", []interface{}{1}},
+		{"time is not a struct source", "SELECT ?", []interface{}{time.Time{}}, "SELECT ?", []interface{}{time.Time{}}},
+		{"struct without placeholders", "SELECT ?", []interface{}{namedUser{}}, "SELECT ?", []interface{}{namedUser{}}},
+		{"two maps", "SELECT ?, ?", []interface{}{map[string]int{}, map[string]int{}}, "SELECT ?, ?", []interface{}{map[string]int{}, map[string]int{}}},
+	}
+	for _, tt := range tests {
+		query, args, err := m.bind(tt.query, tt.args)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if query != tt.want || !reflect.DeepEqual(args, tt.wargs) {
+			t.Errorf("%s: got %q %v, want %q %v", tt.name, query, args, tt.want, tt.wargs)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package mysql
+
+import (
+	"strings"
+)
+
+// NamingStrategy maps Go struct and field names to table and column names.
+// FieldName is the reverse mapping, used when a column has no matching
+// column name so it can still be matched against the field name.
+type NamingStrategy interface {
+	TableName(name string) string
+	ColumnName(name string) string
+	FieldName(column string) string
+}
+
+// SnakeNaming maps CreatedAt to created_at.
+type SnakeNaming struct{}
+
+func (SnakeNaming) TableName(name string) string {
+	return snakeString(name)
+}
+
+func (SnakeNaming) ColumnName(name string) string {
+	return snakeString(name)
+}
+
+func (SnakeNaming) FieldName(column string) string {
+	return camelString(column)
+}
+
+// ExactNaming uses the Go names unchanged. It is the default strategy.
+type ExactNaming struct{}
+
+func (ExactNaming) TableName(name string) string {
+	return name
+}
+
+func (ExactNaming) ColumnName(name string) string {
+	return name
+}
+
+func (ExactNaming) FieldName(column string) string {
+	return column
+}
+
+// LowerNaming maps CreatedAt to createdat.
+type LowerNaming struct{}
+
+func (LowerNaming) TableName(name string) string {
+	return strings.ToLower(name)
+}
+
+func (LowerNaming) ColumnName(name string) string {
+	return strings.ToLower(name)
+}
+
+func (LowerNaming) FieldName(column string) string {
+	return column
+}
+
+var DefaultNaming NamingStrategy = ExactNaming{}
+
+func (m *Mysql) SetNamingStrategy(naming NamingStrategy) {
+	m.naming = naming
+}
+
+func (m *Mysql) NamingStrategy() NamingStrategy {
+	if m == nil || m.naming == nil {
+		return DefaultNaming
+	}
+	return m.naming
+}
@@ -0,0 +1,60 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSnakeNaming(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"ID", "id"},
+		{"UserID", "user_id"},
+		{"HTTPServer", "http_server"},
+		{"CreatedAt", "created_at"},
+		{"OrderItem2", "order_item2"},
+		{"user_id", "user_id"},
+		{"_Private", "_private"},
+		{"Already_Snake", "already_snake"},
+	}
+	for _, tt := range tests {
+		if got := (SnakeNaming{}).ColumnName(tt.name); got != tt.want {
+			t.Errorf("ColumnName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+type mapNaming struct {
+	columns map[string]string
+}
+
+func (n mapNaming) TableName(name string) string { return name }
+
+func (n mapNaming) ColumnName(name string) string {
+	if c, ok := n.columns[name]; ok {
+		return c
+	}
+	return name
+}
+
+func (n mapNaming) FieldName(column string) string { return column }
+
+func TestUncomparableNaming(t *testing.T) {
+	type account struct {
+		UserID int64
+	}
+	naming := mapNaming{columns: map[string]string{"UserID": "uid"}}
+	mi := getModelInfo(reflect.TypeOf(account{}), naming)
+	if mi.Fields[0].Column != "uid" {
+		t.Fatalf("column %q, want uid", mi.Fields[0].Column)
+	}
+	type accountFilter struct {
+		UserID int64 `filter:""`
+	}
+	where, _, err := compileFilter(accountFilter{UserID: 1}, naming)
+	if err != nil || where != "`uid` = ?" {
+		t.Fatal(where, err)
+	}
+}
@@ -0,0 +1,317 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned for a keyset cursor that was not produced by
+// the same ordering.
+var ErrInvalidCursor = errors.New("mysql: invalid cursor")
+
+// Page is one page of an offset paginated query.
+type Page[T any] struct {
+	Items []T
+	Total int64
+	Page  int
+	Size  int
+}
+
+// Pages returns the number of pages.
+func (p Page[T]) Pages() int {
+	if p.Size <= 0 {
+		return 0
+	}
+	return int((p.Total + int64(p.Size) - 1) / int64(p.Size))
+}
+
+// QueryPage returns page (from 1) of size rows of query and the total number
+// of rows, counted with Count. The query should have an ORDER BY so pages
+// are stable; MySQL keeps it when the query is wrapped as a derived table.
+func QueryPage[T any](ctx context.Context, exec Executor, query string, args []interface{}, page int, size int) (Page[T], error) {
+	if page < 1 {
+		page = 1
+	}
+	result := Page[T]{Page: page, Size: size}
+	if exec == nil {
+		return result, errNilExecutor
+	}
+	if size <= 0 {
+		return result, fmt.Errorf("mysql: page size %d must be positive", size)
+	}
+	total, err := count(ctx, exec, query, args...)
+	if err != nil {
+		return result, err
+	}
+	result.Total = total
+	offset := int64(page-1) * int64(size)
+	if offset >= total {
+		return result, nil
+	}
+	// the query is a derived table so that a LIMIT of its own still applies
+	paged := "SELECT * FROM (" + query + ") AS _page LIMIT " + strconv.Itoa(size) + " OFFSET " + strconv.FormatInt(offset, 10)
+	result.Items, err = QueryAll[T](ctx, exec, paged, args...)
+	return result, err
+}
+
+// Keyset describes keyset (cursor) pagination: rows are ordered by Columns,
+// each a column of the query's result with an optional ASC or DESC, and a
+// page starts after the row the Cursor points at. The last column must be
+// unique, usually the primary key:
+//
+//	ks := Keyset{Columns: []string{"created_at DESC", "id DESC"}, Size: 50}
+//	page, err := m.PaginateKeyset("SELECT * FROM orders WHERE user_id = ?", []interface{}{uid}, ks)
+//	ks.Cursor = page.Next
+type Keyset struct {
+	Columns []string
+	Size    int
+	Cursor  string
+}
+
+// KeysetPage is one page of a keyset paginated query. Next is the cursor of
+// the following page, empty on the last page.
+type KeysetPage[T any] struct {
+	Items []T
+	Next  string
+}
+
+type keysetColumn struct {
+	name string
+	desc bool
+}
+
+func (ks Keyset) columns() ([]keysetColumn, error) {
+	if len(ks.Columns) == 0 {
+		return nil, errors.New("mysql: keyset has no columns")
+	}
+	cols := make([]keysetColumn, len(ks.Columns))
+	for i, c := range ks.Columns {
+		fields := strings.Fields(c)
+		if len(fields) == 2 && (strings.EqualFold(fields[1], "ASC") || strings.EqualFold(fields[1], "DESC")) {
+			cols[i] = keysetColumn{name: fields[0], desc: strings.EqualFold(fields[1], "DESC")}
+		} else if len(fields) == 1 {
+			cols[i] = keysetColumn{name: fields[0]}
+		} else {
+			return nil, fmt.Errorf("mysql: bad keyset column %q", c)
+		}
+		if !plainName.MatchString(cols[i].name) {
+			return nil, fmt.Errorf("mysql: bad keyset column %q", c)
+		}
+	}
+	return cols, nil
+}
+
+// keysetSQL wraps query so that it returns the rows after values in the
+// order of cols, one more than size to tell whether there is a next page.
+func keysetSQL(query string, cols []keysetColumn, values []interface{}, size int) (string, []interface{}) {
+	var sb strings.Builder
+	sb.WriteString("SELECT * FROM (" + query + ") AS _page")
+	var args []interface{}
+	if values != nil {
+		// (a > ?) OR (a = ? AND b > ?) OR ...
+		ors := make([]string, len(cols))
+		for i, c := range cols {
+			var ands []string
+			for _, prev := range cols[:i] {
+				ands = append(ands, quoteIdent(prev.name)+" = ?")
+			}
+			op := " > ?"
+			if c.desc {
+				op = " < ?"
+			}
+			ands = append(ands, quoteIdent(c.name)+op)
+			args = append(args, values[:i+1]...)
+			ors[i] = "(" + strings.Join(ands, " AND ") + ")"
+		}
+		sb.WriteString(" WHERE " + strings.Join(ors, " OR "))
+	}
+	order := make([]string, len(cols))
+	for i, c := range cols {
+		order[i] = quoteIdent(c.name)
+		if c.desc {
+			order[i] += " DESC"
+		}
+	}
+	sb.WriteString(" ORDER BY " + strings.Join(order, ", "))
+	sb.WriteString(" LIMIT " + strconv.Itoa(size+1))
+	return sb.String(), args
+}
+
+// QueryKeyset returns the page of query after ks.Cursor. Rows can be maps or
+// models; the ordering columns are read back from the last row to make the
+// next cursor.
+func QueryKeyset[T any](ctx context.Context, exec Executor, query string, args []interface{}, ks Keyset) (KeysetPage[T], error) {
+	var result KeysetPage[T]
+	if exec == nil {
+		return result, errNilExecutor
+	}
+	if ks.Size <= 0 {
+		return result, fmt.Errorf("mysql: page size %d must be positive", ks.Size)
+	}
+	cols, err := ks.columns()
+	if err != nil {
+		return result, err
+	}
+	var values []interface{}
+	if ks.Cursor != "" {
+		if values, err = decodeCursor(ks.Cursor, len(cols)); err != nil {
+			return result, err
+		}
+	}
+	// bind first so that named parameters survive the extra args
+	query, args, err = exec.mysql().bind(query, args)
+	if err != nil {
+		return result, err
+	}
+	pageQuery, pageArgs := keysetSQL(query, cols, values, ks.Size)
+	items, err := QueryAll[T](ctx, exec, pageQuery, append(append([]interface{}{}, args...), pageArgs...)...)
+	if err != nil {
+		return result, err
+	}
+	if len(items) > ks.Size {
+		items = items[:ks.Size]
+		last := reflect.ValueOf(&items[len(items)-1]).Elem()
+		if result.Next, err = encodeCursor(exec.mysql(), last, cols); err != nil {
+			return result, err
+		}
+	}
+	result.Items = items
+	return result, nil
+}
+
+// cursorValue returns the value of column in a map or model row.
+func cursorValue(m *Mysql, row reflect.Value, column string) (interface{}, error) {
+	for row.Kind() == reflect.Ptr || row.Kind() == reflect.Interface {
+		if row.IsNil() {
+			return nil, fmt.Errorf("mysql: nil row")
+		}
+		row = row.Elem()
+	}
+	switch row.Kind() {
+	case reflect.Map:
+		v := row.MapIndex(reflect.ValueOf(column))
+		if !v.IsValid() {
+			return nil, fmt.Errorf("mysql: keyset column %s is not in the result", column)
+		}
+		return v.Interface(), nil
+	case reflect.Struct:
+		f := getModelInfo(row.Type(), m.NamingStrategy()).lookup(column)
+		if f == nil {
+			return nil, fmt.Errorf("mysql: keyset column %s has no field in %s", column, row.Type())
+		}
+		return row.FieldByIndex(f.Index).Interface(), nil
+	}
+	return nil, fmt.Errorf("mysql: cannot read keyset column %s from %s", column, row.Type())
+}
+
+// encodeCursor writes the ordering values of row as base64 JSON, each value
+// prefixed with its type so it goes back to the server as the same type.
+func encodeCursor(m *Mysql, row reflect.Value, cols []keysetColumn) (string, error) {
+	values := make([]string, len(cols))
+	for i, c := range cols {
+		value, err := cursorValue(m, row, c.name)
+		if err != nil {
+			return "", err
+		}
+		if valuer, ok := value.(driver.Valuer); ok {
+			if value, err = valuer.Value(); err != nil {
+				return "", err
+			}
+		}
+		v := reflect.ValueOf(value)
+		for v.IsValid() && v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+		switch {
+		case !v.IsValid() || v.Kind() == reflect.Ptr:
+			return "", fmt.Errorf("mysql: keyset column %s is NULL", c.name)
+		case v.Type() == timeType:
+			values[i] = "t" + v.Interface().(time.Time).Format(time.RFC3339Nano)
+		case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+			values[i] = "i" + strconv.FormatInt(v.Int(), 10)
+		case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64:
+			values[i] = "u" + strconv.FormatUint(v.Uint(), 10)
+		case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+			values[i] = "f" + strconv.FormatFloat(v.Float(), 'g', -1, 64)
+		case v.Kind() == reflect.Bool:
+			values[i] = "b" + strconv.FormatBool(v.Bool())
+		case v.Kind() == reflect.String:
+			values[i] = "s" + v.String()
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			values[i] = "s" + string(v.Bytes())
+		default:
+			return "", fmt.Errorf("mysql: cannot use %s of keyset column %s in a cursor", v.Type(), c.name)
+		}
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(cursor string, n int) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var values []string
+	if err := json.Unmarshal(b, &values); err != nil || len(values) != n {
+		return nil, ErrInvalidCursor
+	}
+	result := make([]interface{}, n)
+	for i, s := range values {
+		if s == "" {
+			return nil, ErrInvalidCursor
+		}
+		var err error
+		switch s[0] {
+		case 't':
+			result[i], err = time.Parse(time.RFC3339Nano, s[1:])
+		case 'i':
+			result[i], err = strconv.ParseInt(s[1:], 10, 64)
+		case 'u':
+			result[i], err = strconv.ParseUint(s[1:], 10, 64)
+		case 'f':
+			result[i], err = strconv.ParseFloat(s[1:], 64)
+		case 'b':
+			result[i], err = strconv.ParseBool(s[1:])
+		case 's':
+			result[i] = s[1:]
+		default:
+			err = ErrInvalidCursor
+		}
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return result, nil
+}
+
+// Paginate returns page (from 1) of size rows of query as maps, with the
+// total number of rows. Use QueryPage for models.
+func (m *Mysql) Paginate(query string, args []interface{}, page int, size int) (Page[map[string]interface{}], error) {
+	return QueryPage[map[string]interface{}](context.Background(), m, query, args, page, size)
+}
+
+// PaginateKeyset returns the page of query after ks.Cursor as maps. Use
+// QueryKeyset for models.
+func (m *Mysql) PaginateKeyset(query string, args []interface{}, ks Keyset) (KeysetPage[map[string]interface{}], error) {
+	return QueryKeyset[map[string]interface{}](context.Background(), m, query, args, ks)
+}
+
+func (tx *Tx) Paginate(query string, args []interface{}, page int, size int) (Page[map[string]interface{}], error) {
+	return QueryPage[map[string]interface{}](context.Background(), tx, query, args, page, size)
+}
+
+func (tx *Tx) PaginateKeyset(query string, args []interface{}, ks Keyset) (KeysetPage[map[string]interface{}], error) {
+	return QueryKeyset[map[string]interface{}](context.Background(), tx, query, args, ks)
+}
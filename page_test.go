@@ -0,0 +1,181 @@
+package mysql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestKeysetSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		values  []interface{}
+		size    int
+		query   string
+		args    []interface{}
+	}{
+		{
+			name:    "first page",
+			columns: []string{"id"},
+			size:    10,
+			query:   "SELECT * FROM (SELECT * FROM t) AS _page ORDER BY `id` LIMIT 11",
+		},
+		{
+			name:    "one column",
+			columns: []string{"id"},
+			values:  []interface{}{int64(5)},
+			size:    10,
+			query:   "SELECT * FROM (SELECT * FROM t) AS _page WHERE (`id` > ?) ORDER BY `id` LIMIT 11",
+			args:    []interface{}{int64(5)},
+		},
+		{
+			name:    "mixed directions",
+			columns: []string{"created_at DESC", "score asc", "id DESC"},
+			values:  []interface{}{"c", 2.5, int64(9)},
+			size:    2,
+			query: "SELECT * FROM (SELECT * FROM t) AS _page WHERE (`created_at` < ?) OR (`created_at` = ? AND `score` > ?) " +
+				"OR (`created_at` = ? AND `score` = ? AND `id` < ?) ORDER BY `created_at` DESC, `score`, `id` DESC LIMIT 3",
+			args: []interface{}{"c", "c", 2.5, "c", 2.5, int64(9)},
+		},
+	}
+	for _, tt := range tests {
+		cols, err := Keyset{Columns: tt.columns}.columns()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		query, args := keysetSQL("SELECT * FROM t", cols, tt.values, tt.size)
+		if query != tt.query {
+			t.Errorf("%s:\n got %s\nwant %s", tt.name, query, tt.query)
+		}
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s: args %v, want %v", tt.name, args, tt.args)
+		}
+	}
+}
+
+func TestKeysetColumnsErrors(t *testing.T) {
+	for _, columns := range [][]string{nil, {"id; DROP"}, {"id DESC NULLS"}, {"id UP"}, {"LOWER(name)"}} {
+		if _, err := (Keyset{Columns: columns}).columns(); err == nil {
+			t.Errorf("%q: want error", columns)
+		}
+	}
+}
+
+type cursorRow struct {
+	ID      int64     `field:"id"`
+	Created time.Time `field:"created_at"`
+	Score   *float64  `field:"score"`
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+	score := 2.5
+	tests := []struct {
+		name    string
+		row     interface{}
+		columns []string
+		values  []interface{}
+	}{
+		{
+			name:    "map row",
+			row:     map[string]interface{}{"id": int64(7), "name": []byte("bob"), "ok": true, "n": uint32(3), "at": created},
+			columns: []string{"at", "name", "ok", "n", "id"},
+			values:  []interface{}{created, "bob", true, uint64(3), int64(7)},
+		},
+		{
+			name:    "model row",
+			row:     &cursorRow{ID: 9, Created: created, Score: &score},
+			columns: []string{"created_at DESC", "score", "id"},
+			values:  []interface{}{created, 2.5, int64(9)},
+		},
+		{
+			name:    "text with quotes",
+			row:     map[string]interface{}{"s": `a"b'c`},
+			columns: []string{"s"},
+			values:  []interface{}{`a"b'c`},
+		},
+	}
+	for _, tt := range tests {
+		cols, err := Keyset{Columns: tt.columns}.columns()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cursor, err := encodeCursor(&Mysql{}, reflect.ValueOf(tt.row), cols)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		values, err := decodeCursor(cursor, len(cols))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(values, tt.values) {
+			t.Errorf("%s: values %#v, want %#v", tt.name, values, tt.values)
+		}
+	}
+}
+
+func TestEncodeCursorErrors(t *testing.T) {
+	cols := []keysetColumn{{name: "score"}}
+	tests := []struct {
+		name string
+		row  interface{}
+	}{
+		{"null value", &cursorRow{}},
+		{"missing map key", map[string]interface{}{"id": 1}},
+		{"missing field", struct{ ID int }{1}},
+		{"unsupported type", map[string]interface{}{"score": []int{1}}},
+	}
+	for _, tt := range tests {
+		if _, err := encodeCursor(&Mysql{}, reflect.ValueOf(tt.row), cols); err == nil {
+			t.Errorf("%s: want error", tt.name)
+		}
+	}
+}
+
+func TestDecodeCursorErrors(t *testing.T) {
+	valid, err := encodeCursor(&Mysql{}, reflect.ValueOf(map[string]interface{}{"id": 1}), []keysetColumn{{name: "id"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		cursor string
+		n      int
+	}{
+		{"not base64", "!!!", 1},
+		{"not json", "bm90IGpzb24", 1},
+		{"wrong count", valid, 2},
+		{"empty value", "WyIiXQ", 1},
+		{"unknown type", "WyJ4MSJd", 1},
+		{"bad integer", "WyJpeCJd", 1},
+	}
+	for _, tt := range tests {
+		if _, err := decodeCursor(tt.cursor, tt.n); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: got error %v, want ErrInvalidCursor", tt.name, err)
+		}
+	}
+}
+
+func TestPagePages(t *testing.T) {
+	tests := []struct {
+		total int64
+		size  int
+		pages int
+	}{
+		{0, 10, 0},
+		{1, 10, 1},
+		{10, 10, 1},
+		{11, 10, 2},
+		{5, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := (Page[int]{Total: tt.total, Size: tt.size}).Pages(); got != tt.pages {
+			t.Errorf("Pages() of %d rows by %d = %d, want %d", tt.total, tt.size, got, tt.pages)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var errNilExecutor = errors.New("mysql: nil Executor")
+
+var mapType = reflect.TypeOf(map[string]interface{}(nil))
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// rowDecoder turns the current row into a T. Structs and pointers to structs
+// are decoded through the model mapping, map[string]interface{} the same way
+// as QueryForMap; any other type, including structs implementing sql.Scanner
+// such as sql.NullString, needs a single column and is scanned directly.
+type rowDecoder[T any] func(rows *sql.Rows) (T, error)
+
+func newRowDecoder[T any](rows *sql.Rows, exec Executor) (rowDecoder[T], error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	elem := t
+	isPtr := false
+	if elem.Kind() == reflect.Ptr {
+		isPtr = true
+		elem = elem.Elem()
+	}
+
+	if t == mapType {
+		cols, err := rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(cols))
+		scanArgs := make([]interface{}, len(cols))
+		for i := range values {
+			scanArgs[i] = &values[i]
+		}
+		return func(rows *sql.Rows) (T, error) {
+			if err := rows.Scan(scanArgs...); err != nil {
+				var zero T
+				return zero, err
+			}
+			row := make(map[string]interface{}, len(cols))
+			for i, key := range cols {
+				if b, ok := values[i].([]byte); ok {
+					row[key] = string(b)
+				} else {
+					row[key] = values[i]
+				}
+			}
+			return interface{}(row).(T), nil
+		}, nil
+	}
+
+	if elem.Kind() == reflect.Struct && elem != timeType && !reflect.PtrTo(elem).Implements(scannerType) {
+		scanner, err := newModelScanner(rows, getModelInfo(elem, exec.mysql().NamingStrategy()), exec)
+		if err != nil {
+			return nil, err
+		}
+		return func(rows *sql.Rows) (T, error) {
+			var result T
+			v := reflect.ValueOf(&result).Elem()
+			if isPtr {
+				v.Set(reflect.New(elem))
+				v = v.Elem()
+			}
+			err := scanner.scan(rows, v)
+			return result, err
+		}, nil
+	}
+
+	if isPtr && elem.Kind() == reflect.Ptr {
+		return nil, fmt.Errorf("mysql: cannot decode rows into %s", t)
+	}
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if len(cols) != 1 {
+		return nil, fmt.Errorf("mysql: cannot decode %d columns into %s, need exactly one", len(cols), t)
+	}
+	return func(rows *sql.Rows) (T, error) {
+		var result T
+		err := rows.Scan(&result)
+		return result, err
+	}, nil
+}
+
+// QueryOne returns the first row of the result. The bool is false when the
+// query matched nothing; the error is then ErrNotFound if the Mysql was set
+// up with SetNotFoundError.
+func QueryOne[T any](ctx context.Context, exec Executor, query string, args ...interface{}) (T, bool, error) {
+	var zero T
+	if exec == nil {
+		return zero, false, errNilExecutor
+	}
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return zero, false, err
+	}
+	defer rows.Close()
+
+	decode, err := newRowDecoder[T](rows, exec)
+	if err != nil {
+		return zero, false, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return zero, false, exec.mysql().convertError(err)
+		}
+		return zero, false, exec.mysql().notFound()
+	}
+	result, err := decode(rows)
+	if err != nil {
+		return zero, false, err
+	}
+	return result, true, nil
+}
+
+// QueryAll returns every row of the result.
+func QueryAll[T any](ctx context.Context, exec Executor, query string, args ...interface{}) ([]T, error) {
+	if exec == nil {
+		return nil, errNilExecutor
+	}
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	decode, err := newRowDecoder[T](rows, exec)
+	if err != nil {
+		return nil, err
+	}
+	var results []T
+	for rows.Next() {
+		result, err := decode(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, exec.mysql().convertError(rows.Err())
+}
+
+// QueryScalar returns the single column of the first row, or ErrNotFound.
+func QueryScalar[T any](ctx context.Context, exec Executor, query string, args ...interface{}) (T, error) {
+	var result T
+	if exec == nil {
+		return result, errNilExecutor
+	}
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return result, err
+	}
+	if len(cols) != 1 {
+		return result, fmt.Errorf("mysql: scalar query returned %d columns, need exactly one", len(cols))
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return result, exec.mysql().convertError(err)
+		}
+		return result, ErrNotFound
+	}
+	err = rows.Scan(&result)
+	return result, err
+}
@@ -0,0 +1,285 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	hasOne    = "hasone"
+	hasMany   = "hasmany"
+	belongsTo = "belongsto"
+)
+
+// relationInfo describes a field tagged with `relation`:
+//
+//	Items   []Item `relation:"hasMany,foreignKey:order_id"`
+//	Address *Address `relation:"hasOne"`
+//	User    User   `relation:"belongsTo,foreignKey:user_id,references:id"`
+//
+// For hasOne and hasMany the foreign key is a column of the related table
+// and references defaults to the primary key of this model; the foreign key
+// defaults to this model's name plus Id. For belongsTo the foreign key is a
+// column of this model, defaulting to the field name plus Id, and references
+// defaults to the primary key of the related model.
+type relationInfo struct {
+	Name       string
+	Kind       string
+	Index      []int
+	Type       reflect.Type
+	Elem       reflect.Type
+	ForeignKey string
+	References string
+}
+
+func (mi *modelInfo) parseRelation(sf reflect.StructField, index []int, tag string) {
+	rel := &relationInfo{
+		Name:  sf.Name,
+		Index: index,
+		Type:  sf.Type,
+	}
+	parts := strings.Split(tag, ",")
+	rel.Kind = strings.ToLower(strings.TrimSpace(parts[0]))
+	for _, opt := range parts[1:] {
+		name, value, _ := strings.Cut(strings.TrimSpace(opt), ":")
+		switch strings.ToLower(name) {
+		case "foreignkey":
+			rel.ForeignKey = value
+		case "references":
+			rel.References = value
+		}
+	}
+	t := sf.Type
+	switch rel.Kind {
+	case hasMany:
+		if t.Kind() != reflect.Slice {
+			mi.err = fmt.Errorf("mysql: relation %s.%s: hasMany needs a slice, got %s", mi.Type, sf.Name, sf.Type)
+			return
+		}
+		t = t.Elem()
+	case hasOne, belongsTo:
+		if t.Kind() == reflect.Slice {
+			mi.err = fmt.Errorf("mysql: relation %s.%s: %s needs a struct, got %s", mi.Type, sf.Name, strings.TrimSpace(parts[0]), sf.Type)
+			return
+		}
+	default:
+		mi.err = fmt.Errorf("mysql: relation %s.%s has unknown kind %q", mi.Type, sf.Name, rel.Kind)
+		return
+	}
+	rel.Elem = indirectType(t)
+	if rel.Elem.Kind() != reflect.Struct || rel.Elem == timeType {
+		mi.err = fmt.Errorf("mysql: relation %s.%s: %s is not a model struct", mi.Type, sf.Name, sf.Type)
+		return
+	}
+	if mi.relations == nil {
+		mi.relations = make(map[string]*relationInfo)
+	}
+	mi.relations[strings.ToLower(sf.Name)] = rel
+}
+
+// keys returns the key fields joining parent rows of mi to related rows.
+func (rel *relationInfo) keys(mi *modelInfo, related *modelInfo) (parentKey *fieldInfo, relatedKey *fieldInfo, err error) {
+	single := func(m *modelInfo) string {
+		if len(m.pks) == 1 {
+			return m.pks[0].Column
+		}
+		return ""
+	}
+	var parentCol, relatedCol string
+	switch rel.Kind {
+	case hasOne, hasMany:
+		parentCol, relatedCol = rel.References, rel.ForeignKey
+		if parentCol == "" {
+			parentCol = single(mi)
+		}
+		if relatedCol == "" {
+			relatedCol = mi.naming.ColumnName(mi.Type.Name() + "Id")
+		}
+	case belongsTo:
+		parentCol, relatedCol = rel.ForeignKey, rel.References
+		if parentCol == "" {
+			parentCol = mi.naming.ColumnName(rel.Name + "Id")
+		}
+		if relatedCol == "" {
+			relatedCol = single(related)
+		}
+	default:
+		return nil, nil, fmt.Errorf("mysql: relation %s.%s has unknown kind %q", mi.Type, rel.Name, rel.Kind)
+	}
+	if parentKey = mi.lookup(parentCol); parentKey == nil {
+		return nil, nil, fmt.Errorf("mysql: relation %s.%s: %s has no key column %q", mi.Type, rel.Name, mi.Type, parentCol)
+	}
+	if relatedKey = related.lookup(relatedCol); relatedKey == nil {
+		return nil, nil, fmt.Errorf("mysql: relation %s.%s: %s has no key column %q", mi.Type, rel.Name, related.Type, relatedCol)
+	}
+	return parentKey, relatedKey, nil
+}
+
+// relationKey returns the key of a row for grouping, false for a nil key.
+func relationKey(v reflect.Value) (interface{}, string, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, "", false
+		}
+		v = v.Elem()
+	}
+	value := v.Interface()
+	return value, ToStr(value), true
+}
+
+// preload loads the relations named by paths for parents, all structs of
+// type t. A path like Items.Product preloads Product on the loaded Items.
+func preload(ctx context.Context, exec Executor, t reflect.Type, parents []reflect.Value, paths []string) error {
+	if len(parents) == 0 || len(paths) == 0 {
+		return nil
+	}
+	var names []string
+	nested := make(map[string][]string)
+	for _, path := range paths {
+		name, rest, _ := strings.Cut(path, ".")
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = nil
+		}
+		if rest != "" {
+			nested[name] = append(nested[name], rest)
+		}
+	}
+	for _, name := range names {
+		if err := preloadRelation(ctx, exec, t, parents, name, nested[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func preloadRelation(ctx context.Context, exec Executor, t reflect.Type, parents []reflect.Value, name string, nested []string) error {
+	naming := exec.mysql().NamingStrategy()
+	mi := getModelInfo(t, naming)
+	if mi.err != nil {
+		return mi.err
+	}
+	rel := mi.relations[strings.ToLower(name)]
+	if rel == nil {
+		return fmt.Errorf("mysql: %s has no relation %q", t, name)
+	}
+	related := getModelInfo(rel.Elem, naming)
+	parentKey, relatedKey, err := rel.keys(mi, related)
+	if err != nil {
+		return err
+	}
+
+	var keys []interface{}
+	seen := make(map[string]bool)
+	for _, p := range parents {
+		value, key, ok := relationKey(p.FieldByIndex(parentKey.Index))
+		if ok && !seen[key] {
+			seen[key] = true
+			keys = append(keys, value)
+		}
+	}
+
+	children := reflect.New(reflect.SliceOf(reflect.PtrTo(rel.Elem)))
+	col := quoteIdent(relatedKey.Column)
+	for start := 0; start < len(keys); start += maxPlaceholders - 1 {
+		end := start + maxPlaceholders - 1
+		if end > len(keys) {
+			end = len(keys)
+		}
+		chunk := keys[start:end]
+		where := Where(col+" IN ("+placeholders(len(chunk))+")", chunk...)
+		if err := findModels(ctx, exec, children.Interface(), []ModelOption{where}); err != nil {
+			return err
+		}
+	}
+	childSlice := children.Elem()
+
+	childValues := make([]reflect.Value, childSlice.Len())
+	groups := make(map[string][]reflect.Value)
+	for i := range childValues {
+		childValues[i] = childSlice.Index(i).Elem()
+		if _, key, ok := relationKey(childValues[i].FieldByIndex(relatedKey.Index)); ok {
+			groups[key] = append(groups[key], childSlice.Index(i))
+		}
+	}
+	if err := preload(ctx, exec, rel.Elem, childValues, nested); err != nil {
+		return err
+	}
+
+	for _, p := range parents {
+		_, key, ok := relationKey(p.FieldByIndex(parentKey.Index))
+		if !ok {
+			continue
+		}
+		group := groups[key]
+		field := p.FieldByIndex(rel.Index)
+		if rel.Kind == hasMany {
+			list := reflect.MakeSlice(rel.Type, 0, len(group))
+			for _, c := range group {
+				if rel.Type.Elem().Kind() == reflect.Ptr {
+					list = reflect.Append(list, c)
+				} else {
+					list = reflect.Append(list, c.Elem())
+				}
+			}
+			field.Set(list)
+		} else if len(group) > 0 {
+			if rel.Type.Kind() == reflect.Ptr {
+				field.Set(group[0])
+			} else {
+				field.Set(group[0].Elem())
+			}
+		}
+	}
+	return nil
+}
+
+// Preload returns a ModelOption that loads the named relation of the found
+// models with one query, for example Preload("Items") or
+// Preload("Items.Product").
+func Preload(path string) ModelOption {
+	return func(o *modelOptions) {
+		o.preload = append(o.preload, path)
+	}
+}
+
+func preloadModels(ctx context.Context, exec Executor, models interface{}, paths []string) error {
+	v := reflect.ValueOf(models)
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		return preload(ctx, exec, v.Elem().Type(), []reflect.Value{v.Elem()}, paths)
+	}
+	sliceValue, elemType, err := modelSlice(models)
+	if err != nil {
+		return err
+	}
+	return preload(ctx, exec, elemType, sliceElements(sliceValue), paths)
+}
+
+// sliceElements returns the structs of a slice of structs or pointers to
+// structs, skipping nil pointers.
+func sliceElements(sliceValue reflect.Value) []reflect.Value {
+	values := make([]reflect.Value, 0, sliceValue.Len())
+	for i := 0; i < sliceValue.Len(); i++ {
+		e := sliceValue.Index(i)
+		if e.Kind() == reflect.Ptr {
+			if e.IsNil() {
+				continue
+			}
+			e = e.Elem()
+		}
+		values = append(values, e)
+	}
+	return values
+}
+
+// Preload loads relations into models already loaded, for example by
+// QueryForModelSlice. models is a pointer to a struct or to a slice.
+func (m *Mysql) Preload(models interface{}, paths ...string) error {
+	return preloadModels(context.Background(), m, models, paths)
+}
+
+func (tx *Tx) Preload(models interface{}, paths ...string) error {
+	return preloadModels(context.Background(), tx, models, paths)
+}
@@ -0,0 +1,181 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Repository provides the common model operations for a struct type T on an
+// Executor, so it works the same on a Mysql and inside a Tx:
+//
+//	users := NewRepository[User](m)
+//	u, err := users.Get(ctx, 42)
+//	active, err := users.Scoped(Where("status = ?", 1)).List(ctx, OrderBy("id DESC"), Limit(20, 0))
+//	err = users.WithExecutor(tx).Create(ctx, &User{Name: "bob"})
+//
+// The table comes from a TableName method or the naming strategy, the
+// primary key from the pk tags. A composite key is passed as a Key in the
+// order of the pk fields. Scopes are applied to every read, and DeleteByPK
+// only deletes a row the scopes can read. Soft deletes, timestamps, hooks
+// and versions work as in the model APIs.
+type Repository[T any] struct {
+	exec   Executor
+	scopes []ModelOption
+}
+
+func NewRepository[T any](exec Executor) *Repository[T] {
+	return &Repository[T]{exec: exec}
+}
+
+// WithExecutor returns a copy of r running on exec, such as a Tx.
+func (r *Repository[T]) WithExecutor(exec Executor) *Repository[T] {
+	return &Repository[T]{exec: exec, scopes: r.scopes}
+}
+
+// Scoped returns a copy of r with opts added to every read.
+func (r *Repository[T]) Scoped(opts ...ModelOption) *Repository[T] {
+	scopes := append(append([]ModelOption{}, r.scopes...), opts...)
+	return &Repository[T]{exec: r.exec, scopes: scopes}
+}
+
+func (r *Repository[T]) info() (*modelInfo, error) {
+	if r.exec == nil {
+		return nil, errNilExecutor
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("mysql: Repository needs a struct type, got %s", t)
+	}
+	return getModelInfo(t, r.exec.mysql().NamingStrategy()), nil
+}
+
+func (r *Repository[T]) options(opts []ModelOption) []ModelOption {
+	return append(append([]ModelOption{}, r.scopes...), opts...)
+}
+
+// Table returns the table of T.
+func (r *Repository[T]) Table() string {
+	mi, err := r.info()
+	if err != nil {
+		return ""
+	}
+	return mi.tableName(reflect.New(mi.Type).Elem())
+}
+
+// Get returns the row with primary key pk, or ErrNotFound.
+func (r *Repository[T]) Get(ctx context.Context, pk interface{}, opts ...ModelOption) (*T, error) {
+	mi, err := r.info()
+	if err != nil {
+		return nil, err
+	}
+	where, args, err := mi.pkCondition(pk)
+	if err != nil {
+		return nil, err
+	}
+	return r.First(ctx, append([]ModelOption{Where(where, args...)}, opts...)...)
+}
+
+// GetMany returns the rows with the given primary keys, in no particular
+// order. Keys with no row are left out.
+func (r *Repository[T]) GetMany(ctx context.Context, pks []interface{}, opts ...ModelOption) ([]*T, error) {
+	if _, err := r.info(); err != nil {
+		return nil, err
+	}
+	var results []*T
+	if err := getModels(ctx, r.exec, &results, pks, r.options(opts)); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// First returns the first matching row, or ErrNotFound.
+func (r *Repository[T]) First(ctx context.Context, opts ...ModelOption) (*T, error) {
+	if _, err := r.info(); err != nil {
+		return nil, err
+	}
+	model := new(T)
+	found, err := findModel(ctx, r.exec, model, r.options(opts))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrNotFound
+	}
+	return model, nil
+}
+
+// List returns the matching rows.
+func (r *Repository[T]) List(ctx context.Context, opts ...ModelOption) ([]*T, error) {
+	if _, err := r.info(); err != nil {
+		return nil, err
+	}
+	var results []*T
+	err := findModels(ctx, r.exec, &results, r.options(opts))
+	return results, err
+}
+
+// Count returns the number of matching rows.
+func (r *Repository[T]) Count(ctx context.Context, opts ...ModelOption) (int64, error) {
+	mi, err := r.info()
+	if err != nil {
+		return -1, err
+	}
+	o, err := newModelOptions(r.options(opts))
+	if err != nil {
+		return -1, err
+	}
+	query := "SELECT COUNT(*) FROM " + quoteIdent(r.Table())
+	if conds := mi.conditions(o); len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	n, err := QueryScalar[int64](ctx, r.exec, query, o.args...)
+	if err != nil {
+		return -1, err
+	}
+	return n, nil
+}
+
+// Create inserts model, writing back the auto-increment id.
+func (r *Repository[T]) Create(ctx context.Context, model *T) error {
+	if _, err := r.info(); err != nil {
+		return err
+	}
+	_, err := insertModel(ctx, r.exec, model)
+	return err
+}
+
+// CreateMany inserts models in batches, see BulkInsert.
+func (r *Repository[T]) CreateMany(ctx context.Context, models []*T, opts BulkOptions) (BulkResult, error) {
+	if _, err := r.info(); err != nil {
+		return BulkResult{}, err
+	}
+	return BulkInsert(ctx, r.exec, models, opts)
+}
+
+// Update updates model by its primary key, see UpdateModel.
+func (r *Repository[T]) Update(ctx context.Context, model *T, fields ...string) (int64, error) {
+	if _, err := r.info(); err != nil {
+		return -1, err
+	}
+	return updateModel(ctx, r.exec, model, UpdateOptions{Fields: fields})
+}
+
+// Delete deletes model by its primary key, see DeleteModel.
+func (r *Repository[T]) Delete(ctx context.Context, model *T, opts ...ModelOption) (int64, error) {
+	if _, err := r.info(); err != nil {
+		return -1, err
+	}
+	return deleteModel(ctx, r.exec, model, opts)
+}
+
+// DeleteByPK loads the row with primary key pk through the scopes and
+// deletes it, or returns ErrNotFound.
+func (r *Repository[T]) DeleteByPK(ctx context.Context, pk interface{}, opts ...ModelOption) (int64, error) {
+	model, err := r.Get(ctx, pk)
+	if err != nil {
+		return -1, err
+	}
+	return deleteModel(ctx, r.exec, model, opts)
+}
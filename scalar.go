@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+)
+
+func queryInt64(exec Executor, query string, args ...interface{}) (int64, error) {
+	v, err := QueryScalar[sql.NullInt64](context.Background(), exec, query, args...)
+	return v.Int64, err
+}
+
+func queryString(exec Executor, query string, args ...interface{}) (string, error) {
+	v, err := QueryScalar[sql.NullString](context.Background(), exec, query, args...)
+	return v.String, err
+}
+
+func queryInt64s(exec Executor, query string, args ...interface{}) ([]int64, error) {
+	values, err := QueryAll[sql.NullInt64](context.Background(), exec, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]int64, len(values))
+	for i, v := range values {
+		results[i] = v.Int64
+	}
+	return results, nil
+}
+
+func queryStrings(exec Executor, query string, args ...interface{}) ([]string, error) {
+	values, err := QueryAll[sql.NullString](context.Background(), exec, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]string, len(values))
+	for i, v := range values {
+		results[i] = v.String
+	}
+	return results, nil
+}
+
+func exists(exec Executor, query string, args ...interface{}) (bool, error) {
+	v, err := QueryScalar[bool](context.Background(), exec, "SELECT EXISTS("+query+")", args...)
+	return v, err
+}
+
+func count(ctx context.Context, exec Executor, query string, args ...interface{}) (int64, error) {
+	v, err := QueryScalar[sql.NullInt64](ctx, exec, "SELECT COUNT(*) FROM ("+query+") AS _count", args...)
+	return v.Int64, err
+}
+
+// QueryInt64 returns the first column of the first row, or ErrNotFound.
+// NULL is returned as 0.
+func (m *Mysql) QueryInt64(query string, args ...interface{}) (int64, error) {
+	return queryInt64(m, query, args...)
+}
+
+// QueryString returns the first column of the first row, or ErrNotFound.
+// NULL is returned as "".
+func (m *Mysql) QueryString(query string, args ...interface{}) (string, error) {
+	return queryString(m, query, args...)
+}
+
+func (m *Mysql) QueryInt64s(query string, args ...interface{}) ([]int64, error) {
+	return queryInt64s(m, query, args...)
+}
+
+func (m *Mysql) QueryStrings(query string, args ...interface{}) ([]string, error) {
+	return queryStrings(m, query, args...)
+}
+
+// Exists reports whether the query returns at least one row.
+func (m *Mysql) Exists(query string, args ...interface{}) (bool, error) {
+	return exists(m, query, args...)
+}
+
+// Count returns the number of rows the query returns.
+func (m *Mysql) Count(query string, args ...interface{}) (int64, error) {
+	return count(context.Background(), m, query, args...)
+}
+
+func (tx *Tx) QueryInt64(query string, args ...interface{}) (int64, error) {
+	return queryInt64(tx, query, args...)
+}
+
+func (tx *Tx) QueryString(query string, args ...interface{}) (string, error) {
+	return queryString(tx, query, args...)
+}
+
+func (tx *Tx) QueryInt64s(query string, args ...interface{}) ([]int64, error) {
+	return queryInt64s(tx, query, args...)
+}
+
+func (tx *Tx) QueryStrings(query string, args ...interface{}) ([]string, error) {
+	return queryStrings(tx, query, args...)
+}
+
+func (tx *Tx) Exists(query string, args ...interface{}) (bool, error) {
+	return exists(tx, query, args...)
+}
+
+func (tx *Tx) Count(query string, args ...interface{}) (int64, error) {
+	return count(context.Background(), tx, query, args...)
+}
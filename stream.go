@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+// ErrStopIteration can be returned from a QueryEach callback to stop reading
+// rows without QueryEach reporting an error.
+var ErrStopIteration = errors.New("mysql: stop iteration")
+
+// QueryEach calls fn for every row without loading the whole result into
+// memory. Iteration stops at the first error returned by fn.
+func QueryEach[T any](ctx context.Context, exec Executor, fn func(row T) error, query string, args ...interface{}) error {
+	if exec == nil {
+		return errNilExecutor
+	}
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	decode, err := newRowDecoder[T](rows, exec)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		row, err := decode(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			if err == ErrStopIteration {
+				return nil
+			}
+			return err
+		}
+	}
+	return exec.mysql().convertError(rows.Err())
+}
+
+// QueryIter is the range-over-func form of QueryEach. Breaking out of the
+// loop closes the rows; a query or scan error is yielded once and ends the
+// iteration.
+func QueryIter[T any](ctx context.Context, exec Executor, query string, args ...interface{}) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		stopped := false
+		err := QueryEach(ctx, exec, func(row T) error {
+			if !yield(row, nil) {
+				stopped = true
+				return ErrStopIteration
+			}
+			return nil
+		}, query, args...)
+		if err != nil && !stopped {
+			yield(zero, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"reflect"
+	"time"
+
+	driver "github.com/go-sql-driver/mysql"
+)
+
+// Location returns the time zone of the connection, the loc parameter of the
+// DSN. The driver writes time arguments in this zone, so the model APIs fill
+// timestamps and decode DATETIME columns in it too. It is DefaultTimeLoc
+// before Open.
+func (m *Mysql) Location() *time.Location {
+	if m == nil || m.loc == nil {
+		return DefaultTimeLoc
+	}
+	return m.loc
+}
+
+// setConnStr records dbConn and the time zone it configures.
+func (m *Mysql) setConnStr(dbConn string) error {
+	cfg, err := driver.ParseDSN(dbConn)
+	if err != nil {
+		return err
+	}
+	m.connStr = dbConn
+	m.loc = cfg.Loc
+	return nil
+}
+
+func (m *Mysql) now() time.Time {
+	return time.Now().In(m.Location())
+}
+
+// setTimestamp sets f to now according to its type and :milli variant.
+func setTimestamp(v reflect.Value, f *fieldInfo, option string, now time.Time) error {
+	milli := f.option(option) == "milli"
+	field := v.FieldByIndex(f.Index)
+	if isIntegerKind(indirectType(f.Type).Kind()) {
+		if milli {
+			return setFieldValue(field, now.UnixMilli(), now.Location())
+		}
+		return setFieldValue(field, now.Unix(), now.Location())
+	}
+	if milli {
+		now = now.Truncate(time.Millisecond)
+	} else {
+		now = now.Truncate(time.Second)
+	}
+	switch field.Type() {
+	case timeType:
+		field.Set(reflect.ValueOf(now))
+	case reflect.PtrTo(timeType):
+		field.Set(reflect.ValueOf(&now))
+	default:
+		return setFieldValue(field, now, now.Location())
+	}
+	return nil
+}
+
+// fillCreateTimes sets the zero autoCreateTime and autoUpdateTime fields
+// before an insert.
+func (mi *modelInfo) fillCreateTimes(v reflect.Value, now time.Time) error {
+	for _, f := range mi.createTimes {
+		if v.FieldByIndex(f.Index).IsZero() {
+			if err := setTimestamp(v, f, "autoCreateTime", now); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range mi.updateTimes {
+		if v.FieldByIndex(f.Index).IsZero() {
+			if err := setTimestamp(v, f, "autoUpdateTime", now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fillUpdateTimes sets the autoUpdateTime fields before an update.
+func (mi *modelInfo) fillUpdateTimes(v reflect.Value, now time.Time) error {
+	for _, f := range mi.updateTimes {
+		if err := setTimestamp(v, f, "autoUpdateTime", now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
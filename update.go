@@ -0,0 +1,263 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrNoRowsAffected is returned by UpdateModelWith when MustAffect is set and
+// the update matched nothing.
+var ErrNoRowsAffected = errors.New("mysql: no rows affected")
+
+// Snapshot can be embedded in a model. Rows loaded into the model record the
+// loaded values, and UpdateModel without explicit fields then only writes the
+// fields that changed since.
+type Snapshot struct {
+	values map[string]interface{}
+}
+
+var snapshotType = reflect.TypeOf(Snapshot{})
+
+func (mi *modelInfo) takeSnapshot(v reflect.Value) {
+	if mi.snapshot == nil {
+		return
+	}
+	values := make(map[string]interface{}, len(mi.Fields))
+	for _, f := range mi.Fields {
+		values[f.Column] = v.FieldByIndex(f.Index).Interface()
+	}
+	v.FieldByIndex(mi.snapshot).Addr().Interface().(*Snapshot).values = values
+}
+
+// changed reports whether f differs from the snapshot. Without a snapshot
+// every field counts as changed.
+func (mi *modelInfo) changed(v reflect.Value, f *fieldInfo) bool {
+	if mi.snapshot == nil {
+		return true
+	}
+	values := v.FieldByIndex(mi.snapshot).Addr().Interface().(*Snapshot).values
+	old, ok := values[f.Column]
+	if !ok {
+		return true
+	}
+	cur := v.FieldByIndex(f.Index).Interface()
+	if t, ok := cur.(time.Time); ok {
+		if o, ok := old.(time.Time); ok {
+			return !t.Equal(o)
+		}
+	}
+	return !reflect.DeepEqual(cur, old)
+}
+
+func containsField(fields []*fieldInfo, f *fieldInfo) bool {
+	for _, field := range fields {
+		if field == f {
+			return true
+		}
+	}
+	return false
+}
+
+func (mi *modelInfo) pkWhere(v reflect.Value) (string, []interface{}, error) {
+	values := make(Key, len(mi.pks))
+	for i, f := range mi.pks {
+		values[i] = v.FieldByIndex(f.Index).Interface()
+	}
+	return mi.pkCondition(values)
+}
+
+// Key is the value of a composite primary key, in the order of the pk
+// fields.
+type Key []interface{}
+
+// keyValues returns the column values of primary key pk, given as the value
+// of a single pk or as a Key or []interface{} for a composite one.
+func (mi *modelInfo) keyValues(pk interface{}) ([]interface{}, error) {
+	if len(mi.pks) == 0 {
+		return nil, fmt.Errorf("mysql: %s has no field tagged pk", mi.Type)
+	}
+	var values []interface{}
+	switch k := pk.(type) {
+	case Key:
+		values = k
+	case []interface{}:
+		values = k
+	default:
+		values = []interface{}{pk}
+	}
+	if len(values) != len(mi.pks) {
+		return nil, fmt.Errorf("mysql: %s has a primary key of %d columns, got %d values", mi.Type, len(mi.pks), len(values))
+	}
+	return values, nil
+}
+
+// pkIn returns the condition matching any of keys, `id` IN (?, ?) or
+// (`a`, `b`) IN ((?, ?), (?, ?)) for a composite key.
+func (mi *modelInfo) pkIn(keys [][]interface{}) (string, []interface{}) {
+	args := make([]interface{}, 0, len(keys)*len(mi.pks))
+	for _, k := range keys {
+		args = append(args, k...)
+	}
+	if len(mi.pks) == 1 {
+		return quoteIdent(mi.pks[0].Column) + " IN (" + placeholders(len(keys)) + ")", args
+	}
+	cols := make([]string, len(mi.pks))
+	for i, f := range mi.pks {
+		cols[i] = quoteIdent(f.Column)
+	}
+	tuples := make([]string, len(keys))
+	for i := range keys {
+		tuples[i] = "(" + placeholders(len(mi.pks)) + ")"
+	}
+	return "(" + strings.Join(cols, ", ") + ") IN (" + strings.Join(tuples, ", ") + ")", args
+}
+
+// pkCondition returns the condition matching primary key pk, see keyValues.
+func (mi *modelInfo) pkCondition(pk interface{}) (string, []interface{}, error) {
+	values, err := mi.keyValues(pk)
+	if err != nil {
+		return "", nil, err
+	}
+	conds := make([]string, len(mi.pks))
+	for i, f := range mi.pks {
+		conds[i] = quoteIdent(f.Column) + " = ?"
+	}
+	return strings.Join(conds, " AND "), values, nil
+}
+
+type UpdateOptions struct {
+	// Fields lists the columns or field names to update. When empty, the
+	// changed fields are updated if the model embeds Snapshot, otherwise
+	// every field except the primary key, softDelete and autoCreateTime
+	// fields. autoUpdateTime fields are always updated.
+	Fields []string
+	// MustAffect makes a zero RowsAffected an ErrNoRowsAffected error. MySQL
+	// counts only changed rows unless the DSN sets clientFoundRows=true.
+	MustAffect bool
+}
+
+func updateModel(ctx context.Context, exec Executor, model interface{}, opts UpdateOptions) (int64, error) {
+	v, err := modelValue(model)
+	if err != nil {
+		return -1, err
+	}
+	mi := getModelInfo(v.Type(), exec.mysql().NamingStrategy())
+	if mi.err != nil {
+		return -1, mi.err
+	}
+	where, whereArgs, err := mi.pkWhere(v)
+	if err != nil {
+		return -1, err
+	}
+	if err := beforeUpdate(exec, v); err != nil {
+		return -1, err
+	}
+
+	var fields []*fieldInfo
+	if len(opts.Fields) > 0 {
+		for _, name := range opts.Fields {
+			f := mi.lookup(name)
+			if f == nil {
+				return -1, fmt.Errorf("mysql: %s has no field %q", mi.Type, name)
+			}
+			fields = append(fields, f)
+		}
+	} else {
+		for _, f := range mi.Fields {
+			if f.has("pk") || f == mi.auto || f == mi.softDelete || f == mi.version || f.has("autoCreateTime") || f.has("autoUpdateTime") || !mi.changed(v, f) {
+				continue
+			}
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 0 {
+		return 0, nil
+	}
+	if err := mi.fillUpdateTimes(v, exec.mysql().now()); err != nil {
+		return -1, err
+	}
+	for _, f := range mi.updateTimes {
+		if !containsField(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+	if err := validate(exec, v); err != nil {
+		return -1, err
+	}
+
+	var sets []string
+	args := make([]interface{}, 0, len(fields)+len(whereArgs)+1)
+	for _, f := range fields {
+		if f == mi.version {
+			continue
+		}
+		sets = append(sets, quoteIdent(f.Column)+" = ?")
+		args = append(args, v.FieldByIndex(f.Index).Interface())
+	}
+	args = append(args, whereArgs...)
+
+	var version reflect.Value
+	if mi.version != nil {
+		version = v.FieldByIndex(mi.version.Index)
+		if !isIntegerKind(version.Kind()) {
+			return -1, fmt.Errorf("mysql: version field %s.%s must be an integer", mi.Type, mi.version.Name)
+		}
+		col := quoteIdent(mi.version.Column)
+		sets = append(sets, col+" = "+col+" + 1")
+		where += " AND " + col + " = ?"
+		args = append(args, version.Interface())
+	}
+
+	query := "UPDATE " + quoteIdent(mi.tableName(v)) + " SET " + strings.Join(sets, ", ") + " WHERE " + where
+	res, err := exec.ExecContext(ctx, query, args...)
+	if err != nil {
+		return -1, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+	if n == 0 && mi.version != nil {
+		return 0, &StaleObjectError{Table: mi.tableName(v), Version: version.Interface()}
+	}
+	if n == 0 && opts.MustAffect {
+		return 0, ErrNoRowsAffected
+	}
+	if mi.version != nil {
+		if err := setFieldValue(version, ToInt64(version.Interface())+1, exec.mysql().Location()); err != nil {
+			return n, err
+		}
+	}
+	mi.takeSnapshot(v)
+	if err := afterUpdate(exec, v); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// UpdateModel updates the row identified by the model's pk fields. With no
+// fields it writes the changed fields, see UpdateOptions.
+//
+// If the model has a version field, the update also requires the row to
+// still have the model's version and increments it. When another writer has
+// changed the row since, nothing is updated and a *StaleObjectError is
+// returned.
+func (m *Mysql) UpdateModel(model interface{}, fields ...string) (int64, error) {
+	return updateModel(context.Background(), m, model, UpdateOptions{Fields: fields})
+}
+
+func (m *Mysql) UpdateModelWith(model interface{}, opts UpdateOptions) (int64, error) {
+	return updateModel(context.Background(), m, model, opts)
+}
+
+func (tx *Tx) UpdateModel(model interface{}, fields ...string) (int64, error) {
+	return updateModel(context.Background(), tx, model, UpdateOptions{Fields: fields})
+}
+
+func (tx *Tx) UpdateModelWith(model interface{}, opts UpdateOptions) (int64, error) {
+	return updateModel(context.Background(), tx, model, opts)
+}
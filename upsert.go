@@ -0,0 +1,278 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// UpsertResult tells what INSERT ... ON DUPLICATE KEY UPDATE did, from the
+// rows affected: 1 for an insert, 2 for an update and 0 when the existing row
+// already had the new values. With clientFoundRows=true in the DSN an
+// unchanged row also reports 1, so it shows up as UpsertInserted.
+type UpsertResult int
+
+const (
+	UpsertUnchanged UpsertResult = iota
+	UpsertInserted
+	UpsertUpdated
+)
+
+func (r UpsertResult) String() string {
+	switch r {
+	case UpsertInserted:
+		return "inserted"
+	case UpsertUpdated:
+		return "updated"
+	default:
+		return "unchanged"
+	}
+}
+
+func upsertResult(res sql.Result) (UpsertResult, error) {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return UpsertUnchanged, err
+	}
+	switch n {
+	case 1:
+		return UpsertInserted, nil
+	case 2:
+		return UpsertUpdated, nil
+	default:
+		return UpsertUnchanged, nil
+	}
+}
+
+// UpsertBuilder builds an INSERT ... ON DUPLICATE KEY UPDATE statement.
+//
+//	b := NewUpsert("counters").Value("id", id).Value("hits", 1).
+//		UpdateExpr("hits", "`hits` + ?", 1)
+//	res, err := m.ExecUpsert(b)
+type UpsertBuilder struct {
+	table   string
+	cols    []string
+	args    []interface{}
+	updates []upsertUpdate
+}
+
+type upsertUpdate struct {
+	column string
+	expr   string
+	args   []interface{}
+}
+
+func NewUpsert(table string) *UpsertBuilder {
+	return &UpsertBuilder{table: table}
+}
+
+// Value adds a column to the inserted row.
+func (b *UpsertBuilder) Value(column string, value interface{}) *UpsertBuilder {
+	b.cols = append(b.cols, column)
+	b.args = append(b.args, value)
+	return b
+}
+
+// Update sets columns to the value the insert would have written.
+func (b *UpsertBuilder) Update(columns ...string) *UpsertBuilder {
+	for _, c := range columns {
+		b.updates = append(b.updates, upsertUpdate{column: c})
+	}
+	return b
+}
+
+// UpdateExpr sets column to a raw SQL expression, such as "`hits` + ?".
+func (b *UpsertBuilder) UpdateExpr(column string, expr string, args ...interface{}) *UpsertBuilder {
+	b.updates = append(b.updates, upsertUpdate{column: column, expr: expr, args: args})
+	return b
+}
+
+// ToSQL returns the statement and its arguments. With alias set it uses the
+// row alias syntax of MySQL 8.0.19 and later, otherwise VALUES(column).
+func (b *UpsertBuilder) ToSQL(alias bool) (string, []interface{}, error) {
+	if len(b.cols) == 0 {
+		return "", nil, fmt.Errorf("mysql: upsert into %s has no values", b.table)
+	}
+	if len(b.updates) == 0 {
+		return "", nil, fmt.Errorf("mysql: upsert into %s has no update columns", b.table)
+	}
+	cols := make([]string, len(b.cols))
+	for i, c := range b.cols {
+		cols[i] = quoteIdent(c)
+	}
+	args := append([]interface{}{}, b.args...)
+
+	sets := make([]string, len(b.updates))
+	for i, u := range b.updates {
+		col := quoteIdent(u.column)
+		switch {
+		case u.expr != "":
+			sets[i] = col + " = " + u.expr
+			args = append(args, u.args...)
+		case alias:
+			sets[i] = col + " = `new`." + col
+		default:
+			sets[i] = col + " = VALUES(" + col + ")"
+		}
+	}
+
+	query := "INSERT INTO " + quoteIdent(b.table) + " (" + strings.Join(cols, ", ") + ") VALUES (" + placeholders(len(cols)) + ")"
+	if alias {
+		query += " AS `new`"
+	}
+	query += " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+	return query, args, nil
+}
+
+// useInsertAlias reports whether the server accepts the row alias syntax.
+// VALUES() is deprecated from 8.0.20 but still works everywhere, so it is
+// the fallback whenever the version is unknown.
+func useInsertAlias(ctx context.Context, exec Executor) bool {
+	m := exec.mysql()
+	if m == nil {
+		return false
+	}
+	version, err := m.serverVersion(ctx)
+	if err != nil {
+		return false
+	}
+	return versionAtLeast(version, 8, 0, 19)
+}
+
+func execUpsert(ctx context.Context, exec Executor, b *UpsertBuilder) (UpsertResult, error) {
+	query, args, err := b.ToSQL(useInsertAlias(ctx, exec))
+	if err != nil {
+		return UpsertUnchanged, err
+	}
+	res, err := exec.ExecContext(ctx, query, args...)
+	if err != nil {
+		return UpsertUnchanged, err
+	}
+	return upsertResult(res)
+}
+
+func upsertModel(ctx context.Context, exec Executor, model interface{}, updateColumns []string) (UpsertResult, error) {
+	v, err := modelValue(model)
+	if err != nil {
+		return UpsertUnchanged, err
+	}
+	mi := getModelInfo(v.Type(), exec.mysql().NamingStrategy())
+	if err := beforeInsert(exec, v); err != nil {
+		return UpsertUnchanged, err
+	}
+	now := exec.mysql().now()
+	if err := mi.fillUpdateTimes(v, now); err != nil {
+		return UpsertUnchanged, err
+	}
+	if err := mi.fillCreateTimes(v, now); err != nil {
+		return UpsertUnchanged, err
+	}
+	if err := validate(exec, v); err != nil {
+		return UpsertUnchanged, err
+	}
+	fields, auto := mi.insertFields(v)
+
+	b := NewUpsert(mi.tableName(v))
+	for _, f := range fields {
+		b.Value(f.Column, v.FieldByIndex(f.Index).Interface())
+	}
+	var updates []*fieldInfo
+	if len(updateColumns) > 0 {
+		for _, name := range updateColumns {
+			f := mi.lookup(name)
+			if f == nil {
+				return UpsertUnchanged, fmt.Errorf("mysql: %s has no field %q", mi.Type, name)
+			}
+			updates = append(updates, f)
+		}
+		for _, f := range mi.updateTimes {
+			if !containsField(updates, f) {
+				updates = append(updates, f)
+			}
+		}
+	} else {
+		for _, f := range fields {
+			if !f.has("pk") && !f.has("autoCreateTime") {
+				updates = append(updates, f)
+			}
+		}
+	}
+	for _, f := range updates {
+		if f != mi.version {
+			b.Update(f.Column)
+		}
+	}
+	var version reflect.Value
+	if mi.version != nil {
+		version = v.FieldByIndex(mi.version.Index)
+		if !isIntegerKind(version.Kind()) {
+			return UpsertUnchanged, fmt.Errorf("mysql: version field %s.%s must be an integer", mi.Type, mi.version.Name)
+		}
+		// the row's version is not the model's, so the new one is passed
+		// back as the insert id
+		col := quoteIdent(mi.version.Column)
+		b.UpdateExpr(mi.version.Column, "LAST_INSERT_ID("+col+" + 1)")
+	}
+
+	query, args, err := b.ToSQL(useInsertAlias(ctx, exec))
+	if err != nil {
+		return UpsertUnchanged, err
+	}
+	res, err := exec.ExecContext(ctx, query, args...)
+	if err != nil {
+		return UpsertUnchanged, err
+	}
+	result, err := upsertResult(res)
+	if err != nil {
+		return result, err
+	}
+	if result == UpsertInserted && auto != nil {
+		id, err := res.LastInsertId()
+		if err != nil {
+			return result, err
+		}
+		if err := setFieldValue(v.FieldByIndex(auto.Index), id, exec.mysql().Location()); err != nil {
+			return result, err
+		}
+	}
+	if result == UpsertUpdated && mi.version != nil {
+		next, err := res.LastInsertId()
+		if err != nil {
+			return result, err
+		}
+		if err := setFieldValue(version, next, exec.mysql().Location()); err != nil {
+			return result, err
+		}
+	}
+	mi.takeSnapshot(v)
+	switch result {
+	case UpsertInserted:
+		err = afterInsert(exec, v)
+	case UpsertUpdated:
+		err = afterUpdate(exec, v)
+	}
+	return result, err
+}
+
+// Upsert inserts model or, on a duplicate key, updates updateColumns (by
+// column or field name) to the new values. Without updateColumns every
+// inserted column except the pk and autoCreateTime fields is updated.
+// autoUpdateTime fields are always updated and a version field is
+// incremented and read back into model.
+func (m *Mysql) Upsert(model interface{}, updateColumns ...string) (UpsertResult, error) {
+	return upsertModel(context.Background(), m, model, updateColumns)
+}
+
+func (m *Mysql) ExecUpsert(b *UpsertBuilder) (UpsertResult, error) {
+	return execUpsert(context.Background(), m, b)
+}
+
+func (tx *Tx) Upsert(model interface{}, updateColumns ...string) (UpsertResult, error) {
+	return upsertModel(context.Background(), tx, model, updateColumns)
+}
+
+func (tx *Tx) ExecUpsert(b *UpsertBuilder) (UpsertResult, error) {
+	return execUpsert(context.Background(), tx, b)
+}
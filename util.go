@@ -86,11 +86,11 @@ func (f *StrTo) Set(v string) {
 }
 
 func (f *StrTo) Clear() {
-	*f = StrTo(0x1E)
+	*f = StrTo("\x1e")
 }
 
 func (f StrTo) Exist() bool {
-	return string(f) != string(0x1E)
+	return string(f) != "\x1e"
 }
 
 func (f StrTo) Bool() (bool, error) {
@@ -219,8 +219,12 @@ func snakeString(s string) string {
 	num := len(s)
 	for i := 0; i < num; i++ {
 		d := s[i]
-		if i > 0 && d >= 'A' && d <= 'Z' && j {
-			data = append(data, '_')
+		if i > 0 && isUpper(d) && j && s[i-1] != '_' {
+			// a run of capitals is one word: UserID is user_id and
+			// HTTPServer is http_server
+			if !isUpper(s[i-1]) || i+1 < num && s[i+1] >= 'a' && s[i+1] <= 'z' {
+				data = append(data, '_')
+			}
 		}
 		if d != '_' {
 			j = true
@@ -230,6 +234,10 @@ func snakeString(s string) string {
 	return strings.ToLower(string(data[:len(data)]))
 }
 
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
 func camelString(s string) string {
 	data := make([]byte, 0, len(s))
 	j := false
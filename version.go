@@ -0,0 +1,69 @@
+package mysql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// SetServerVersion sets the server version instead of asking the server,
+// for example "8.0.32" or "10.6.12-MariaDB".
+func (m *Mysql) SetServerVersion(version string) {
+	m.versionMu.Lock()
+	m.version = version
+	m.versionMu.Unlock()
+}
+
+// ServerVersion returns SELECT VERSION(), queried once and then cached.
+func (m *Mysql) ServerVersion() (string, error) {
+	return m.serverVersion(context.Background())
+}
+
+// serverVersion always queries on m, never inside a Tx, so a failure cannot
+// mark a transaction as failed. The lock is not held during the query;
+// concurrent first calls may each ask the server and the first answer wins.
+func (m *Mysql) serverVersion(ctx context.Context) (string, error) {
+	m.versionMu.Lock()
+	version := m.version
+	m.versionMu.Unlock()
+	if version != "" {
+		return version, nil
+	}
+	version, err := QueryScalar[string](ctx, m, "SELECT VERSION()")
+	if err != nil {
+		return "", err
+	}
+	m.versionMu.Lock()
+	defer m.versionMu.Unlock()
+	if m.version == "" {
+		m.version = version
+	}
+	return m.version, nil
+}
+
+// versionAtLeast reports whether a MySQL version string is at least
+// major.minor.patch. MariaDB versions never are, since their numbering
+// does not follow MySQL's features.
+func versionAtLeast(version string, major, minor, patch int) bool {
+	if strings.Contains(strings.ToLower(version), "mariadb") {
+		return false
+	}
+	if i := strings.IndexAny(version, "-+ "); i >= 0 {
+		version = version[:i]
+	}
+	want := []int{major, minor, patch}
+	parts := strings.Split(version, ".")
+	for i, w := range want {
+		if i >= len(parts) {
+			return false
+		}
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return false
+		}
+		if n != w {
+			return n > w
+		}
+	}
+	return true
+}
@@ -0,0 +1,29 @@
+package mysql
+
+import "testing"
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"8.0.19", true},
+		{"8.0.18", false},
+		{"8.0.32-log", true},
+		{"8.0.19+build", true},
+		{"8.1", true},
+		{"8.0", false},
+		{"8.1.0", true},
+		{"9.0.0", true},
+		{"5.7.30-log", false},
+		{"10.6.12-MariaDB", false},
+		{"11.2.2-MariaDB-1:11.2.2+maria~ubu2204", false},
+		{"", false},
+		{"eight", false},
+	}
+	for _, tt := range tests {
+		if got := versionAtLeast(tt.version, 8, 0, 19); got != tt.want {
+			t.Errorf("versionAtLeast(%q, 8, 0, 19) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}